@@ -0,0 +1,320 @@
+// Package cassette records the HTTP traffic between the provider and a live
+// Prismatic API during an acceptance test, and replays it so the test can run
+// again without a tenant or network.
+//
+// A Server is a reverse proxy the provider is pointed at in place of the API. In
+// record mode it forwards GraphQL requests, token exchanges and component uploads
+// upstream and saves each request/response pair to a cassette file, with access
+// and refresh tokens and non-example email addresses scrubbed. In replay mode it
+// answers each request with the recorded response for the same method, path and
+// body.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Server records or replays.
+type Mode string
+
+const (
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+)
+
+// ReplayToken is the access token to configure the provider with when replaying;
+// the recorded tokens are scrubbed, and the replaying server ignores credentials.
+const ReplayToken = "cassette-replay-token"
+
+// serverPlaceholder stands in for the proxy's own URL, which differs between runs,
+// in recorded responses.
+const serverPlaceholder = "{{cassette}}"
+
+const uploadPathPrefix = "/uploads/"
+
+// Interaction is one recorded request and its response. Upload bodies are not
+// recorded, only that the upload happened and how it was answered.
+type Interaction struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Request  string `json:"request,omitempty"`
+	Status   int    `json:"status"`
+	Response string `json:"response,omitempty"`
+}
+
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Server is a running recording or replaying proxy.
+type Server struct {
+	// URL is the base URL to configure the provider with in place of the API's.
+	URL string
+
+	mode       Mode
+	path       string
+	upstream   *url.URL
+	httpServer *httptest.Server
+	scrubber   *scrubber
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	uploads      []string
+}
+
+// Record starts a proxy to the API at upstream that records to the cassette at
+// path when saved.
+func Record(path, upstream string) (*Server, error) {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream URL %q: %w", upstream, err)
+	}
+	s := &Server{mode: ModeRecord, path: path, upstream: u, scrubber: newScrubber()}
+	s.start(s.serveRecord)
+	return s, nil
+}
+
+// Replay starts a server answering from the cassette at path.
+func Replay(path string) (*Server, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("unable to parse cassette %s: %w", path, err)
+	}
+	s := &Server{
+		mode:         ModeReplay,
+		path:         path,
+		interactions: file.Interactions,
+		used:         make([]bool, len(file.Interactions)),
+	}
+	s.start(s.serveReplay)
+	return s, nil
+}
+
+func (s *Server) start(handler http.HandlerFunc) {
+	s.httpServer = httptest.NewServer(handler)
+	s.URL = s.httpServer.URL
+}
+
+// Close shuts the server down without saving.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Save writes the interactions recorded so far to the cassette file, creating its
+// directory if needed. It does nothing when replaying.
+func (s *Server) Save() error {
+	if s.mode != ModeRecord {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, err := json.MarshalIndent(cassetteFile{Interactions: s.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(content, '\n'), 0644)
+}
+
+func (s *Server) serveRecord(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	target, isUpload, err := s.recordTarget(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, target, bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, header := range []string{"Authorization", "Content-Type", "Accept"} {
+		if v := r.Header.Get(header); v != "" {
+			req.Header.Set(header, v)
+		}
+	}
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if !isUpload {
+		respBody = s.rewriteUploadUrls(respBody)
+	}
+
+	interaction := Interaction{Method: r.Method, Path: r.URL.Path, Status: resp.StatusCode}
+	if !isUpload {
+		interaction.Request = s.scrubber.scrub(string(body))
+		interaction.Response = strings.ReplaceAll(s.scrubber.scrub(string(respBody)), s.URL, serverPlaceholder)
+	}
+	s.mu.Lock()
+	s.interactions = append(s.interactions, interaction)
+	s.mu.Unlock()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(respBody)
+}
+
+// recordTarget resolves the upstream URL for a request to the proxy: uploads go
+// to the signed URL the API handed out, everything else to the API itself.
+func (s *Server) recordTarget(r *http.Request) (string, bool, error) {
+	if n, ok := strings.CutPrefix(r.URL.Path, uploadPathPrefix); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		i, err := strconv.Atoi(n)
+		if err != nil || i < 1 || i > len(s.uploads) {
+			return "", true, fmt.Errorf("unknown upload %s", r.URL.Path)
+		}
+		return s.uploads[i-1], true, nil
+	}
+
+	target := *s.upstream
+	target.Path = strings.TrimSuffix(target.Path, "/") + r.URL.Path
+	target.RawQuery = r.URL.RawQuery
+	return target.String(), false, nil
+}
+
+var uploadUrlPattern = regexp.MustCompile(`("\w*[uU]ploadUrl"\s*:\s*)("(?:[^"\\]|\\.)*")`)
+
+// rewriteUploadUrls replaces the signed upload URLs in an API response with URLs
+// on the proxy, so uploads are recorded too.
+func (s *Server) rewriteUploadUrls(body []byte) []byte {
+	return uploadUrlPattern.ReplaceAllFunc(body, func(match []byte) []byte {
+		parts := uploadUrlPattern.FindSubmatch(match)
+		var original string
+		if err := json.Unmarshal(parts[2], &original); err != nil || original == "" {
+			return match
+		}
+
+		s.mu.Lock()
+		s.uploads = append(s.uploads, original)
+		proxied := fmt.Sprintf("%s%s%d", s.URL, uploadPathPrefix, len(s.uploads))
+		s.mu.Unlock()
+
+		quoted, _ := json.Marshal(proxied)
+		return append(append([]byte{}, parts[1]...), quoted...)
+	})
+}
+
+func (s *Server) serveReplay(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	interaction, ok := s.match(r.Method, r.URL.Path, string(body))
+	if !ok {
+		message := fmt.Sprintf("cassette %s has no recorded response for %s %s %s", s.path, r.Method, r.URL.Path, body)
+		if isGraphQLPath(r.URL.Path) {
+			// Answer with a GraphQL error so the provider reports it as-is rather than
+			// retrying a server error.
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"errors": []map[string]string{{"message": message}},
+			})
+			return
+		}
+		http.Error(w, message, http.StatusNotFound)
+		return
+	}
+
+	if interaction.Response != "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(interaction.Status)
+	_, _ = io.WriteString(w, strings.ReplaceAll(interaction.Response, serverPlaceholder, s.URL))
+}
+
+// match finds the first unreplayed interaction for the request. Terraform walks
+// independent resources concurrently, so requests are matched by content rather
+// than position; identical requests are answered in the order they were recorded.
+// A GraphQL query that was repeated more often than during recording gets the
+// last recorded answer again.
+func (s *Server) match(method, path, body string) (Interaction, bool) {
+	key := s.requestKey(path, body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last := -1
+	for i, interaction := range s.interactions {
+		if interaction.Method != method || interaction.Path != path || s.requestKey(path, interaction.Request) != key {
+			continue
+		}
+		if !s.used[i] {
+			s.used[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+	if last >= 0 && isGraphQLPath(path) && !isMutation(body) {
+		return s.interactions[last], true
+	}
+	return Interaction{}, false
+}
+
+// requestKey is the part of a request body that identifies it. Token exchanges
+// and uploads are matched on path alone, as their bodies hold credentials or
+// bundle content that is not recorded. Scrubbed addresses all match one another,
+// so requests differing only in them are answered in recorded order.
+func (s *Server) requestKey(path, body string) string {
+	if !isGraphQLPath(path) {
+		return ""
+	}
+	body = matchable(body)
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		return body
+	}
+	canonical, _ := json.Marshal(decoded)
+	return string(canonical)
+}
+
+func isGraphQLPath(path string) bool {
+	return strings.TrimSuffix(path, "/") == "/api"
+}
+
+func isMutation(body string) bool {
+	var req struct {
+		Query string `json:"query"`
+	}
+	_ = json.Unmarshal([]byte(body), &req)
+	return strings.HasPrefix(strings.TrimSpace(req.Query), "mutation")
+}
@@ -0,0 +1,335 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prismatic-io/terraform-provider-prismatic/internal/fakeprismatic"
+	"github.com/shurcooL/graphql"
+)
+
+type bearerTransport struct {
+	token string
+}
+
+func (t bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type CreateOrganizationUserInput struct {
+	Email graphql.String `json:"email"`
+	Name  graphql.String `json:"name"`
+	Role  graphql.ID     `json:"role"`
+}
+
+type PublishComponentInput struct {
+	Definition map[string]interface{} `json:"definition"`
+	Actions    []interface{}          `json:"actions"`
+}
+
+// janePlaceholder is what the session's created user's address is scrubbed to.
+const janePlaceholder = "user-1@example.com"
+
+// session is the traffic of a short provider run: a token exchange, a query, a
+// mutation, a component publish with its upload, and a query repeated after it
+// changed.
+type session struct {
+	userEmail  string
+	uploadUrl  string
+	uploadCode int
+	userCount  []int
+}
+
+func runSession(t *testing.T, baseUrl, token string) session {
+	t.Helper()
+	ctx := context.Background()
+	client := graphql.NewClient(baseUrl+"/api", &http.Client{Transport: bearerTransport{token}})
+	var result session
+
+	resp, err := http.Post(baseUrl+"/auth/refresh", "application/json", strings.NewReader(`{"refresh_token":"fake-refresh-token"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("token exchange returned %s", resp.Status)
+	}
+
+	var roles struct {
+		OrganizationRoles []struct {
+			Id string
+		}
+	}
+	if err := client.Query(ctx, &roles, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var users struct {
+		Users struct {
+			TotalCount int
+		} `graphql:"users(customer_Isnull: true)"`
+	}
+	if err := client.Query(ctx, &users, nil); err != nil {
+		t.Fatal(err)
+	}
+	result.userCount = append(result.userCount, users.Users.TotalCount)
+
+	var created struct {
+		CreateOrganizationUser struct {
+			User struct {
+				Email string
+			}
+		} `graphql:"createOrganizationUser(input: $input)"`
+	}
+	input := CreateOrganizationUserInput{Email: "jane.doe@acme.io", Name: "Jane", Role: graphql.ID(roles.OrganizationRoles[1].Id)}
+	if err := client.Mutate(ctx, &created, map[string]interface{}{"input": input}); err != nil {
+		t.Fatal(err)
+	}
+	result.userEmail = created.CreateOrganizationUser.User.Email
+
+	if err := client.Query(ctx, &users, nil); err != nil {
+		t.Fatal(err)
+	}
+	result.userCount = append(result.userCount, users.Users.TotalCount)
+
+	var published struct {
+		PublishComponent struct {
+			PublishResult struct {
+				PackageUploadUrl string
+			}
+		} `graphql:"publishComponent(input: $input)"`
+	}
+	publish := PublishComponentInput{Definition: map[string]interface{}{"key": "example"}, Actions: []interface{}{}}
+	if err := client.Mutate(ctx, &published, map[string]interface{}{"input": publish}); err != nil {
+		t.Fatal(err)
+	}
+	result.uploadUrl = published.PublishComponent.PublishResult.PackageUploadUrl
+
+	req, _ := http.NewRequest(http.MethodPut, result.uploadUrl, bytes.NewReader([]byte("bundle")))
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	result.uploadCode = resp.StatusCode
+
+	return result
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	api := fakeprismatic.NewServer()
+	defer api.Close()
+	path := filepath.Join(t.TempDir(), "cassettes", "TestExample.json")
+
+	recorder, err := Record(path, api.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := runSession(t, recorder.URL, api.Token)
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	recorder.Close()
+
+	if !strings.HasPrefix(recorded.uploadUrl, recorder.URL+"/uploads/") {
+		t.Errorf("upload URL %q was not routed through the recorder", recorded.uploadUrl)
+	}
+	if content, ok := api.Uploaded("/uploads/" + "Q29tcG9uZW50OjE=" + "/package"); !ok || string(content) != "bundle" {
+		t.Errorf("upload was not forwarded upstream: %q, %v", content, ok)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"fake-access-token", "fake-refresh-token", "jane.doe@acme.io", api.URL} {
+		if bytes.Contains(content, []byte(secret)) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+	if !bytes.Contains(content, []byte(janePlaceholder)) {
+		t.Error("cassette does not contain the scrubbed email placeholder")
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Interactions) != 7 {
+		t.Errorf("recorded %d interactions, want 7", len(file.Interactions))
+	}
+
+	api.Close()
+	player, err := Replay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Close()
+	replayed := runSession(t, player.URL, ReplayToken)
+
+	if replayed.userEmail != janePlaceholder {
+		t.Errorf("replayed email %q, want the scrubbed placeholder", replayed.userEmail)
+	}
+	if got, want := replayed.userCount, recorded.userCount; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("replayed user counts %v, want %v", got, want)
+	}
+	if !strings.HasPrefix(replayed.uploadUrl, player.URL+"/uploads/") || replayed.uploadCode != http.StatusOK {
+		t.Errorf("replayed upload to %q returned %d", replayed.uploadUrl, replayed.uploadCode)
+	}
+}
+
+// TestReplayResponseFirstEmail records addresses that first appear in a response
+// and are then sent in a request, which replay has to match without having seen
+// the response first.
+func TestReplayResponseFirstEmail(t *testing.T) {
+	api := fakeprismatic.NewServer()
+	defer api.Close()
+	ctx := context.Background()
+
+	direct := graphql.NewClient(api.URL+"/api", &http.Client{Transport: bearerTransport{api.Token}})
+	var roles struct {
+		OrganizationRoles []struct {
+			Id string
+		}
+	}
+	if err := direct.Query(ctx, &roles, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, email := range []string{"alice@acme.io", "sam@elsewhere.dev"} {
+		var created struct {
+			CreateOrganizationUser struct {
+				User struct {
+					Id string
+				}
+			} `graphql:"createOrganizationUser(input: $input)"`
+		}
+		input := CreateOrganizationUserInput{Email: graphql.String(email), Name: "User", Role: graphql.ID(roles.OrganizationRoles[1].Id)}
+		if err := direct.Mutate(ctx, &created, map[string]interface{}{"input": input}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Lists every user, then looks one up by an address known up front rather
+	// than one taken from the listing.
+	lookup := func(baseUrl, token string) string {
+		client := graphql.NewClient(baseUrl+"/api", &http.Client{Transport: bearerTransport{token}})
+		var all struct {
+			Users struct {
+				Nodes []struct {
+					Email string
+				}
+			} `graphql:"users(customer_Isnull: true)"`
+		}
+		if err := client.Query(ctx, &all, nil); err != nil {
+			t.Fatal(err)
+		}
+		var one struct {
+			Users struct {
+				Nodes []struct {
+					Id string
+				}
+			} `graphql:"users(email: $email)"`
+		}
+		if err := client.Query(ctx, &one, map[string]interface{}{"email": graphql.String("sam@elsewhere.dev")}); err != nil {
+			t.Fatal(err)
+		}
+		if len(one.Users.Nodes) != 1 {
+			t.Fatalf("found %d users, want 1", len(one.Users.Nodes))
+		}
+		return one.Users.Nodes[0].Id
+	}
+
+	path := filepath.Join(t.TempDir(), "TestResponseFirst.json")
+	recorder, err := Record(path, api.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordedId := lookup(recorder.URL, api.Token)
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	recorder.Close()
+	api.Close()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, email := range []string{"alice@acme.io", "sam@elsewhere.dev"} {
+		if bytes.Contains(content, []byte(email)) {
+			t.Errorf("cassette contains %q", email)
+		}
+	}
+
+	player, err := Replay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Close()
+	if replayedId := lookup(player.URL, ReplayToken); replayedId != recordedId {
+		t.Errorf("replayed user id %q, want %q", replayedId, recordedId)
+	}
+}
+
+func TestReplayUnrecordedRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(path, []byte(`{"interactions":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	player, err := Replay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Close()
+
+	client := graphql.NewClient(player.URL+"/api", nil)
+	var query struct {
+		AuthenticatedUser struct {
+			Id string
+		}
+	}
+	err = client.Query(context.Background(), &query, nil)
+	if err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("got error %v, want a missing recording", err)
+	}
+}
+
+func TestScrub(t *testing.T) {
+	s := newScrubber()
+	cases := []struct {
+		in, want string
+	}{
+		{`{"access_token":"abc","expires_in":3600}`, `{"access_token":"REDACTED","expires_in":3600}`},
+		{`{"refresh_token": "a\"b"}`, `{"refresh_token": "REDACTED"}`},
+		{`{"url":"x?t=eyJhbGciOi.eyJzdWIi.c2lnbmF0dXJl"}`, `{"url":"x?t=REDACTED"}`},
+		{`{"email":"Jane@Acme.io","other":"bob@corp.example.com"}`, `{"email":"user-1@example.com","other":"bob@corp.example.com"}`},
+		{`{"email":"jane@acme.io","name":"sam@elsewhere.dev"}`, `{"email":"user-1@example.com","name":"user-2@example.com"}`},
+	}
+	for _, tc := range cases {
+		if got := s.scrub(tc.in); got != tc.want {
+			t.Errorf("scrub(%s) = %s, want %s", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestMatchable(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{`{"email":"sam@elsewhere.dev"}`, `{"email":"{{email}}"}`},
+		{`{"email":"user-2@example.com"}`, `{"email":"{{email}}"}`},
+		{`{"email":"owner@example.com","token":"abc"}`, `{"email":"owner@example.com","token":"REDACTED"}`},
+	}
+	for _, tc := range cases {
+		if got := matchable(tc.in); got != tc.want {
+			t.Errorf("matchable(%s) = %s, want %s", tc.in, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cassette
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+// emailMarker stands in for every scrubbed address when matching requests.
+const emailMarker = "{{email}}"
+
+var (
+	tokenFieldPattern  = regexp.MustCompile(`("(?:access_token|refresh_token|id_token|accessToken|refreshToken|token)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	jwtPattern         = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	emailPattern       = regexp.MustCompile(`[A-Za-z0-9._%+-]+@((?:[A-Za-z0-9-]+\.)+[A-Za-z]{2,})`)
+	placeholderPattern = regexp.MustCompile(`^user-[0-9]+@example\.com$`)
+)
+
+// exampleDomains are reserved for documentation (RFC 2606), so addresses under
+// them are test fixtures rather than anyone's real address and are left as-is.
+var exampleDomains = []string{"example.com", "example.org", "example.net"}
+
+// scrubber redacts credentials and personal email addresses from recorded bodies.
+// Each distinct address is replaced by the same numbered placeholder everywhere
+// in a recording. The numbering depends only on the order addresses are seen, so
+// a cassette reveals nothing about them; replay does not need to reproduce it, as
+// requests are matched with every scrubbed address treated alike (see matchable).
+type scrubber struct {
+	mu     sync.Mutex
+	emails map[string]string
+}
+
+func newScrubber() *scrubber {
+	return &scrubber{emails: map[string]string{}}
+}
+
+func (s *scrubber) scrub(body string) string {
+	return emailPattern.ReplaceAllStringFunc(redactCredentials(body), s.email)
+}
+
+func (s *scrubber) email(address string) string {
+	if !isPersonalEmail(address) {
+		return address
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(address)
+	if placeholder, ok := s.emails[key]; ok {
+		return placeholder
+	}
+	placeholder := fmt.Sprintf("user-%d@example.com", len(s.emails)+1)
+	s.emails[key] = placeholder
+	return placeholder
+}
+
+// matchable prepares a request body for matching against recorded ones: it
+// redacts credentials and replaces both personal addresses and the placeholders
+// they were recorded as with emailMarker. A replayed request may carry the real
+// address from the test's configuration or a placeholder from a replayed
+// response, and either has to match the recording.
+func matchable(body string) string {
+	return emailPattern.ReplaceAllStringFunc(redactCredentials(body), func(address string) string {
+		if isPersonalEmail(address) || placeholderPattern.MatchString(address) {
+			return emailMarker
+		}
+		return address
+	})
+}
+
+func redactCredentials(body string) string {
+	body = tokenFieldPattern.ReplaceAllString(body, `${1}"`+redacted+`"`)
+	return jwtPattern.ReplaceAllString(body, redacted)
+}
+
+func isPersonalEmail(address string) bool {
+	domain := strings.ToLower(emailPattern.FindStringSubmatch(address)[1])
+	for _, example := range exampleDomains {
+		if domain == example || strings.HasSuffix(domain, "."+example) {
+			return false
+		}
+	}
+	return true
+}
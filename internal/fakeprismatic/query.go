@@ -0,0 +1,388 @@
+package fakeprismatic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// operation is a parsed GraphQL request document.
+type operation struct {
+	mutation   bool
+	selections []*field
+}
+
+// field is one selected field, with its arguments still unresolved (variables are
+// substituted when the field is executed).
+type field struct {
+	alias      string
+	name       string
+	arguments  map[string]value
+	selections []*field
+}
+
+// responseKey is the key the field's result is returned under.
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// value is an argument value: a variable reference, a literal, or a list or object
+// of values.
+type value interface{}
+
+type variableRef string
+
+type enumValue string
+
+// parseOperation parses the subset of GraphQL that shurcooL/graphql generates: a
+// single anonymous query or mutation with variable definitions, fields, aliases
+// and arguments.
+func parseOperation(document string) (*operation, error) {
+	p := &parser{src: document}
+	op := &operation{}
+
+	p.skipIgnored()
+	if name := p.peekName(); name == "query" || name == "mutation" || name == "subscription" {
+		p.readName()
+		if name == "subscription" {
+			return nil, fmt.Errorf("subscriptions are not supported")
+		}
+		op.mutation = name == "mutation"
+		p.skipIgnored()
+		if p.peekName() != "" {
+			p.readName()
+		}
+		p.skipIgnored()
+		if p.peek() == '(' {
+			if err := p.skipBalanced('(', ')'); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+
+	p.skipIgnored()
+	if !p.done() {
+		return nil, p.errorf("unexpected %q after operation", p.src[p.pos:])
+	}
+	return op, nil
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("syntax error at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// skipIgnored skips whitespace, commas and comments, which GraphQL ignores.
+func (p *parser) skipIgnored() {
+	for !p.done() {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			p.pos++
+		case c == '#':
+			for !p.done() && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipIgnored()
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
+
+func (p *parser) peekName() string {
+	end := p.pos
+	if end < len(p.src) && isNameStart(p.src[end]) {
+		for end < len(p.src) && isNameContinue(p.src[end]) {
+			end++
+		}
+	}
+	return p.src[p.pos:end]
+}
+
+func (p *parser) readName() string {
+	name := p.peekName()
+	p.pos += len(name)
+	return name
+}
+
+// skipBalanced skips a bracketed section such as the variable definitions, whose
+// types the server does not need.
+func (p *parser) skipBalanced(open, close byte) error {
+	depth := 0
+	for !p.done() {
+		switch p.src[p.pos] {
+		case open:
+			depth++
+		case close:
+			depth--
+		}
+		p.pos++
+		if depth == 0 {
+			return nil
+		}
+	}
+	return p.errorf("unterminated %q", open)
+}
+
+func (p *parser) selectionSet() ([]*field, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	var fields []*field
+	for {
+		p.skipIgnored()
+		if p.peek() == '}' {
+			p.pos++
+			return fields, nil
+		}
+		f, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+}
+
+func (p *parser) field() (*field, error) {
+	p.skipIgnored()
+	if strings.HasPrefix(p.src[p.pos:], "...") {
+		return nil, p.errorf("fragments are not supported")
+	}
+	name := p.readName()
+	if name == "" {
+		return nil, p.errorf("expected a field name")
+	}
+	f := &field{name: name}
+
+	p.skipIgnored()
+	if p.peek() == ':' {
+		p.pos++
+		p.skipIgnored()
+		f.alias = name
+		if f.name = p.readName(); f.name == "" {
+			return nil, p.errorf("expected a field name after alias %q", name)
+		}
+		p.skipIgnored()
+	}
+
+	if p.peek() == '(' {
+		arguments, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		f.arguments = arguments
+		p.skipIgnored()
+	}
+
+	if p.peek() == '{' {
+		selections, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+		f.selections = selections
+	}
+	return f, nil
+}
+
+func (p *parser) arguments() (map[string]value, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	arguments := map[string]value{}
+	for {
+		p.skipIgnored()
+		if p.peek() == ')' {
+			p.pos++
+			return arguments, nil
+		}
+		name := p.readName()
+		if name == "" {
+			return nil, p.errorf("expected an argument name")
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		arguments[name] = v
+	}
+}
+
+func (p *parser) value() (value, error) {
+	p.skipIgnored()
+	switch c := p.peek(); {
+	case c == '$':
+		p.pos++
+		name := p.readName()
+		if name == "" {
+			return nil, p.errorf("expected a variable name")
+		}
+		return variableRef(name), nil
+	case c == '"':
+		return p.stringValue()
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.numberValue()
+	case c == '[':
+		p.pos++
+		list := []value{}
+		for {
+			p.skipIgnored()
+			if p.peek() == ']' {
+				p.pos++
+				return list, nil
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+	case c == '{':
+		p.pos++
+		object := map[string]value{}
+		for {
+			p.skipIgnored()
+			if p.peek() == '}' {
+				p.pos++
+				return object, nil
+			}
+			name := p.readName()
+			if name == "" {
+				return nil, p.errorf("expected an object field name")
+			}
+			if err := p.expect(':'); err != nil {
+				return nil, err
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			object[name] = v
+		}
+	case isNameStart(c):
+		switch name := p.readName(); name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			return enumValue(name), nil
+		}
+	default:
+		return nil, p.errorf("expected a value")
+	}
+}
+
+func (p *parser) stringValue() (value, error) {
+	start := p.pos
+	p.pos++
+	for !p.done() && p.src[p.pos] != '"' {
+		if p.src[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.done() {
+		return nil, p.errorf("unterminated string")
+	}
+	p.pos++
+
+	// GraphQL string escapes are a subset of JSON's.
+	var s string
+	if err := json.Unmarshal([]byte(p.src[start:p.pos]), &s); err != nil {
+		return nil, p.errorf("invalid string: %s", err)
+	}
+	return s, nil
+}
+
+func (p *parser) numberValue() (value, error) {
+	start := p.pos
+	p.pos++
+	for !p.done() && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+	n, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %q", p.src[start:p.pos])
+	}
+	return n, nil
+}
+
+// resolveArguments substitutes variables into a field's arguments, producing the
+// same JSON-like values (maps, slices, strings, float64s, bools and nils) that
+// decoding the request's variables does.
+func resolveArguments(arguments map[string]value, variables map[string]interface{}) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(arguments))
+	for name, v := range arguments {
+		resolved[name] = resolveValue(v, variables)
+	}
+	return resolved
+}
+
+func resolveValue(v value, variables map[string]interface{}) interface{} {
+	switch v := v.(type) {
+	case variableRef:
+		return variables[string(v)]
+	case enumValue:
+		return string(v)
+	case []value:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = resolveValue(item, variables)
+		}
+		return list
+	case map[string]value:
+		object := make(map[string]interface{}, len(v))
+		for name, item := range v {
+			object[name] = resolveValue(item, variables)
+		}
+		return object
+	default:
+		return v
+	}
+}
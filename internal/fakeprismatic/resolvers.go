@@ -0,0 +1,1180 @@
+package fakeprismatic
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// errNotFound matches the error the API returns for an unknown ID, which the
+// provider recognizes to drop resources from state.
+var errNotFound = errors.New("Record not found")
+
+type rootResolver func(s *Server, args map[string]interface{}) (interface{}, error)
+
+var queryResolvers map[string]rootResolver
+
+var mutationResolvers map[string]rootResolver
+
+func init() {
+	queryResolvers = map[string]rootResolver{
+		"authenticatedUser": (*Server).authenticatedUser,
+		"organization":      (*Server).organizationQuery,
+		"organizationRoles": (*Server).organizationRoles,
+		"user":              (*Server).userQuery,
+		"users":             (*Server).usersQuery,
+		"customer":          (*Server).customerQuery,
+		"customers":         (*Server).customersQuery,
+		"integration":       (*Server).integrationQuery,
+		"integrations":      (*Server).integrationsQuery,
+		"instance":          (*Server).instanceQuery,
+		"component":         (*Server).componentQuery,
+		"components":        (*Server).componentsQuery,
+	}
+	mutationResolvers = map[string]rootResolver{
+		"createOrganizationUser":       (*Server).createOrganizationUser,
+		"updateUser":                   (*Server).updateUser,
+		"deleteUser":                   (*Server).deleteUser,
+		"createCustomer":               (*Server).createCustomer,
+		"updateCustomer":               (*Server).updateCustomer,
+		"deleteCustomer":               (*Server).deleteCustomer,
+		"importIntegration":            (*Server).importIntegration,
+		"publishIntegration":           (*Server).publishIntegration,
+		"deleteIntegration":            (*Server).deleteIntegration,
+		"createInstance":               (*Server).createInstance,
+		"updateInstance":               (*Server).updateInstance,
+		"deployInstance":               (*Server).deployInstance,
+		"deleteInstance":               (*Server).deleteInstance,
+		"publishComponent":             (*Server).publishComponent,
+		"deleteComponent":              (*Server).deleteComponent,
+		"importOrganizationSigningKey": (*Server).importOrganizationSigningKey,
+		"deleteOrganizationSigningKey": (*Server).deleteOrganizationSigningKey,
+	}
+}
+
+// Argument helpers. Arguments arrive as decoded JSON: objects, lists, strings,
+// float64s, bools and nils.
+
+func stringArg(args map[string]interface{}, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
+
+func inputArg(args map[string]interface{}) map[string]interface{} {
+	input, _ := args["input"].(map[string]interface{})
+	if input == nil {
+		input = map[string]interface{}{}
+	}
+	return input
+}
+
+// optionalString returns the string at name and whether it was given a non-null
+// value, matching the API's "null leaves the field unchanged" update semantics.
+func optionalString(input map[string]interface{}, name string) (string, bool) {
+	s, ok := input[name].(string)
+	return s, ok
+}
+
+func stringList(v interface{}) []string {
+	items, _ := v.([]interface{})
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// payload builds a mutation payload holding the affected record under name and
+// any user-facing errors.
+func payload(name string, record interface{}, fieldErrors ...object) object {
+	if fieldErrors == nil {
+		fieldErrors = []object{}
+	}
+	if len(fieldErrors) > 0 {
+		record = nil
+	}
+	return object{name: record, "errors": fieldErrors}
+}
+
+func fieldError(field string, messages ...string) object {
+	list := make([]interface{}, len(messages))
+	for i, m := range messages {
+		list[i] = m
+	}
+	return object{"field": field, "messages": list}
+}
+
+// connection wraps records in a GraphQL connection with nodes and totalCount.
+func connection(nodes []object) object {
+	if nodes == nil {
+		nodes = []object{}
+	}
+	return object{"nodes": nodes, "totalCount": len(nodes)}
+}
+
+// page returns the page of nodes that the first and after arguments select, as
+// a connection whose pageInfo tells the client how to fetch the next one.
+// Cursors are opaque offsets, like the API's.
+func (s *Server) page(nodes []object, args map[string]interface{}) (object, error) {
+	start := 0
+	if after, ok := args["after"].(string); ok && after != "" {
+		decoded, err := base64.StdEncoding.DecodeString(after)
+		offset, ok := strings.CutPrefix(string(decoded), "arrayconnection:")
+		if err != nil || !ok {
+			return nil, fmt.Errorf("Invalid cursor %q", after)
+		}
+		if start, err = strconv.Atoi(offset); err != nil {
+			return nil, fmt.Errorf("Invalid cursor %q", after)
+		}
+		start++
+	}
+	size := s.pageSize
+	if first, ok := args["first"].(float64); ok {
+		size = int(first)
+	}
+
+	start = min(start, len(nodes))
+	end := min(start+size, len(nodes))
+	var endCursor interface{}
+	if end > start {
+		endCursor = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("arrayconnection:%d", end-1)))
+	}
+	c := connection(nodes[start:end])
+	c["totalCount"] = len(nodes)
+	c["pageInfo"] = object{
+		"hasNextPage": end < len(nodes),
+		"endCursor":   endCursor,
+	}
+	return c, nil
+}
+
+// Organization, roles and users
+
+type organization struct {
+	id   string
+	name string
+}
+
+type role struct {
+	id          string
+	name        string
+	description string
+	level       int
+}
+
+type user struct {
+	id         string
+	email      string
+	name       string
+	phone      string
+	externalId string
+	avatarUrl  string
+	createdAt  string
+	updatedAt  string
+	role       *role
+	customer   *customer
+}
+
+func (s *Server) seed() {
+	s.organization = organization{id: s.newId("Organization"), name: "Example Organization"}
+	for _, r := range []struct {
+		name, description string
+		level             int
+	}{
+		{"Owner", "Full access to the organization, including billing", 30},
+		{"Admin", "Full access to the organization", 20},
+		{"Integrator", "Build and deploy integrations", 10},
+		{"Member", "View integrations and instances", 0},
+	} {
+		s.roles = append(s.roles, &role{id: s.newId("Role"), name: r.name, description: r.description, level: r.level})
+	}
+	now := s.now()
+	s.users = append(s.users, &user{
+		id:        s.newId("User"),
+		email:     "owner@example.com",
+		name:      "Organization Owner",
+		createdAt: now,
+		updatedAt: now,
+		role:      s.roles[0],
+	})
+}
+
+func (r *role) object() object {
+	return object{"id": r.id, "name": r.name, "description": r.description, "level": r.level}
+}
+
+func (u *user) object(s *Server) object {
+	o := object{
+		"id":         u.id,
+		"email":      u.email,
+		"name":       u.name,
+		"phone":      u.phone,
+		"externalId": u.externalId,
+		"avatarUrl":  u.avatarUrl,
+		"createdAt":  u.createdAt,
+		"updatedAt":  u.updatedAt,
+		"role":       u.role.object(),
+		"customer":   nil,
+		"org":        object{"id": s.organization.id, "name": s.organization.name},
+	}
+	if u.customer != nil {
+		o["customer"] = u.customer.object()
+	}
+	return o
+}
+
+func (s *Server) findRole(id string) *role {
+	for _, r := range s.roles {
+		if r.id == id {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *Server) findUser(id string) *user {
+	for _, u := range s.users {
+		if u.id == id {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Server) authenticatedUser(args map[string]interface{}) (interface{}, error) {
+	return s.users[0].object(s), nil
+}
+
+func (s *Server) organizationQuery(args map[string]interface{}) (interface{}, error) {
+	return object{
+		"id":   s.organization.id,
+		"name": s.organization.name,
+		"signingKeys": resolverFunc(func(args map[string]interface{}) (interface{}, error) {
+			nodes := make([]object, 0, len(s.signingKeys))
+			for _, k := range s.signingKeys {
+				nodes = append(nodes, k.object())
+			}
+			return connection(nodes), nil
+		}),
+	}, nil
+}
+
+func (s *Server) organizationRoles(args map[string]interface{}) (interface{}, error) {
+	roles := make([]object, 0, len(s.roles))
+	for _, r := range s.roles {
+		roles = append(roles, r.object())
+	}
+	return roles, nil
+}
+
+func (s *Server) userQuery(args map[string]interface{}) (interface{}, error) {
+	u := s.findUser(stringArg(args, "id"))
+	if u == nil {
+		return nil, errNotFound
+	}
+	return u.object(s), nil
+}
+
+func (s *Server) usersQuery(args map[string]interface{}) (interface{}, error) {
+	var nodes []object
+	for _, u := range s.users {
+		if isNull, ok := args["customer_Isnull"].(bool); ok && isNull != (u.customer == nil) {
+			continue
+		}
+		if email, ok := args["email"].(string); ok && !strings.EqualFold(email, u.email) {
+			continue
+		}
+		if externalId, ok := args["externalId"].(string); ok && externalId != u.externalId {
+			continue
+		}
+		if contains, ok := args["email_Icontains"].(string); ok && !strings.Contains(strings.ToLower(u.email), strings.ToLower(contains)) {
+			continue
+		}
+		if customerId, ok := args["customer"].(string); ok && (u.customer == nil || u.customer.id != customerId) {
+			continue
+		}
+		nodes = append(nodes, u.object(s))
+	}
+	return s.page(nodes, args)
+}
+
+func (s *Server) createOrganizationUser(args map[string]interface{}) (interface{}, error) {
+	input := inputArg(args)
+	email := stringArg(input, "email")
+	if email == "" {
+		return payload("user", nil, fieldError("email", "This field is required.")), nil
+	}
+	for _, u := range s.users {
+		if strings.EqualFold(u.email, email) && u.customer == nil {
+			return payload("user", nil, fieldError("email", "A user with this email already exists.")), nil
+		}
+	}
+	r := s.findRole(stringArg(input, "role"))
+	if r == nil {
+		return payload("user", nil, fieldError("role", "Role not found.")), nil
+	}
+
+	now := s.now()
+	u := &user{
+		id:         s.newId("User"),
+		email:      email,
+		name:       stringArg(input, "name"),
+		phone:      stringArg(input, "phone"),
+		externalId: stringArg(input, "externalId"),
+		createdAt:  now,
+		updatedAt:  now,
+		role:       r,
+	}
+	s.users = append(s.users, u)
+	return payload("user", u.object(s)), nil
+}
+
+// AddCustomerUser adds a user with the given email and external ID to the
+// Customer with the given ID, as a customer admin inviting them would, and
+// returns the user's ID.
+func (s *Server) AddCustomerUser(customerId, email, externalId string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCustomer(customerId)
+	if c == nil {
+		return "", fmt.Errorf("customer %q not found", customerId)
+	}
+	now := s.now()
+	u := &user{
+		id:         s.newId("User"),
+		email:      email,
+		name:       email,
+		externalId: externalId,
+		createdAt:  now,
+		updatedAt:  now,
+		role:       s.roles[len(s.roles)-1],
+		customer:   c,
+	}
+	s.users = append(s.users, u)
+	return u.id, nil
+}
+
+func (s *Server) updateUser(args map[string]interface{}) (interface{}, error) {
+	input := inputArg(args)
+	u := s.findUser(stringArg(input, "id"))
+	if u == nil {
+		return nil, errNotFound
+	}
+	if name, ok := optionalString(input, "name"); ok {
+		u.name = name
+	}
+	if roleId, ok := optionalString(input, "role"); ok {
+		r := s.findRole(roleId)
+		if r == nil {
+			return payload("user", nil, fieldError("role", "Role not found.")), nil
+		}
+		u.role = r
+	}
+	if phone, ok := optionalString(input, "phone"); ok {
+		u.phone = phone
+	}
+	if externalId, ok := optionalString(input, "externalId"); ok {
+		u.externalId = externalId
+	}
+	if avatarUrl, ok := optionalString(input, "avatarUrl"); ok {
+		u.avatarUrl = avatarUrl
+	}
+	u.updatedAt = s.now()
+	return payload("user", u.object(s)), nil
+}
+
+func (s *Server) deleteUser(args map[string]interface{}) (interface{}, error) {
+	id := stringArg(inputArg(args), "id")
+	for i, u := range s.users {
+		if u.id == id {
+			s.users = append(s.users[:i], s.users[i+1:]...)
+			return payload("user", u.object(s)), nil
+		}
+	}
+	return nil, errNotFound
+}
+
+// Customers
+
+type customer struct {
+	id          string
+	name        string
+	description string
+	externalId  string
+	labels      []string
+	avatarUrl   string
+}
+
+// labelList converts labels to the list a resolver returns.
+func labelList(labels []string) []interface{} {
+	list := make([]interface{}, len(labels))
+	for i, l := range labels {
+		list[i] = l
+	}
+	return list
+}
+
+func (c *customer) object() object {
+	return object{
+		"id":          c.id,
+		"name":        c.name,
+		"description": c.description,
+		"externalId":  c.externalId,
+		"labels":      labelList(c.labels),
+		"avatarUrl":   c.avatarUrl,
+	}
+}
+
+func (s *Server) findCustomer(id string) *customer {
+	for _, c := range s.customers {
+		if c.id == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *Server) customerQuery(args map[string]interface{}) (interface{}, error) {
+	c := s.findCustomer(stringArg(args, "id"))
+	if c == nil {
+		return nil, errNotFound
+	}
+	return c.object(), nil
+}
+
+func (s *Server) customersQuery(args map[string]interface{}) (interface{}, error) {
+	var nodes []object
+	for _, c := range s.customers {
+		if externalId, ok := args["externalId"].(string); ok && externalId != c.externalId {
+			continue
+		}
+		if name, ok := args["name"].(string); ok && name != c.name {
+			continue
+		}
+		nodes = append(nodes, c.object())
+	}
+	return connection(nodes), nil
+}
+
+// customerConflict reports a field error if name or externalId is taken by a
+// customer other than self.
+func (s *Server) customerConflict(self *customer, name, externalId string) []object {
+	for _, c := range s.customers {
+		if c == self {
+			continue
+		}
+		if c.name == name {
+			return []object{fieldError("name", "A customer with this name already exists.")}
+		}
+		if externalId != "" && c.externalId == externalId {
+			return []object{fieldError("externalId", "A customer with this external ID already exists.")}
+		}
+	}
+	return nil
+}
+
+func (s *Server) createCustomer(args map[string]interface{}) (interface{}, error) {
+	input := inputArg(args)
+	c := &customer{
+		name:        stringArg(input, "name"),
+		description: stringArg(input, "description"),
+		externalId:  stringArg(input, "externalId"),
+		labels:      stringList(input["labels"]),
+		avatarUrl:   stringArg(input, "avatarUrl"),
+	}
+	if c.name == "" {
+		return payload("customer", nil, fieldError("name", "This field is required.")), nil
+	}
+	if errs := s.customerConflict(c, c.name, c.externalId); errs != nil {
+		return payload("customer", nil, errs...), nil
+	}
+	c.id = s.newId("Customer")
+	s.customers = append(s.customers, c)
+	return payload("customer", c.object()), nil
+}
+
+func (s *Server) updateCustomer(args map[string]interface{}) (interface{}, error) {
+	input := inputArg(args)
+	c := s.findCustomer(stringArg(input, "id"))
+	if c == nil {
+		return nil, errNotFound
+	}
+	name, externalId := c.name, c.externalId
+	if v, ok := optionalString(input, "name"); ok {
+		name = v
+	}
+	if v, ok := optionalString(input, "externalId"); ok {
+		externalId = v
+	}
+	if errs := s.customerConflict(c, name, externalId); errs != nil {
+		return payload("customer", nil, errs...), nil
+	}
+	c.name, c.externalId = name, externalId
+	if v, ok := optionalString(input, "description"); ok {
+		c.description = v
+	}
+	if v, ok := input["labels"]; ok && v != nil {
+		c.labels = stringList(v)
+	}
+	if v, ok := optionalString(input, "avatarUrl"); ok {
+		c.avatarUrl = v
+	}
+	return payload("customer", c.object()), nil
+}
+
+func (s *Server) deleteCustomer(args map[string]interface{}) (interface{}, error) {
+	id := stringArg(inputArg(args), "id")
+	for i, c := range s.customers {
+		if c.id == id {
+			s.customers = append(s.customers[:i], s.customers[i+1:]...)
+			users := s.users[:0]
+			for _, u := range s.users {
+				if u.customer != c {
+					users = append(users, u)
+				}
+			}
+			s.users = users
+			return payload("customer", c.object()), nil
+		}
+	}
+	return nil, errNotFound
+}
+
+// Integrations
+
+// integration is either the editable draft of an Integration (versionNumber 0) or
+// one of its published versions, which share the draft's root.
+type integration struct {
+	id               string
+	root             *integration
+	name             string
+	description      string
+	category         string
+	labels           []string
+	definition       string
+	flows            []*integrationFlow
+	versionNumber    int
+	versionComment   string
+	versionCreatedAt string
+	versionAvailable bool
+}
+
+func (i *integration) object(s *Server) object {
+	return object{
+		"id":                 i.id,
+		"name":               i.name,
+		"description":        i.description,
+		"category":           i.category,
+		"labels":             labelList(i.labels),
+		"definition":         i.definition,
+		"versionNumber":      i.versionNumber,
+		"versionComment":     i.versionComment,
+		"versionCreatedAt":   i.versionCreatedAt,
+		"versionIsAvailable": i.versionAvailable,
+		"versionSequence": resolverFunc(func(args map[string]interface{}) (interface{}, error) {
+			var nodes []object
+			for _, v := range s.versionsOf(i.root) {
+				nodes = append(nodes, v.object(s))
+			}
+			return connection(nodes), nil
+		}),
+		"flows": resolverFunc(func(args map[string]interface{}) (interface{}, error) {
+			var nodes []object
+			for _, f := range i.flows {
+				nodes = append(nodes, f.object())
+			}
+			return connection(nodes), nil
+		}),
+	}
+}
+
+// integrationFlow is a flow of an Integration version, with the URL that triggers
+// it in the Integration's test instance.
+type integrationFlow struct {
+	id            string
+	name          string
+	description   string
+	isSynchronous bool
+	testUrl       string
+}
+
+func (f *integrationFlow) object() object {
+	return object{
+		"id":            f.id,
+		"name":          f.name,
+		"description":   f.description,
+		"isSynchronous": f.isSynchronous,
+		"testUrl":       f.testUrl,
+	}
+}
+
+func (s *Server) findIntegration(id string) *integration {
+	for _, i := range s.integrations {
+		if i.id == id {
+			return i
+		}
+	}
+	return nil
+}
+
+// versionsOf lists the draft and published versions of the Integration, newest
+// first.
+func (s *Server) versionsOf(root *integration) []*integration {
+	var versions []*integration
+	for _, i := range s.integrations {
+		if i.root == root {
+			versions = append(versions, i)
+		}
+	}
+	sort.SliceStable(versions, func(a, b int) bool {
+		if versions[a].versionNumber == 0 || versions[b].versionNumber == 0 {
+			return versions[a].versionNumber == 0 && versions[b].versionNumber != 0
+		}
+		return versions[a].versionNumber > versions[b].versionNumber
+	})
+	return versions
+}
+
+func (s *Server) integrationQuery(args map[string]interface{}) (interface{}, error) {
+	i := s.findIntegration(stringArg(args, "id"))
+	if i == nil {
+		return nil, errNotFound
+	}
+	return i.object(s), nil
+}
+
+func (s *Server) integrationsQuery(args map[string]interface{}) (interface{}, error) {
+	var nodes []object
+	for _, i := range s.integrations {
+		if i.root != i {
+			continue
+		}
+		if name, ok := args["name"].(string); ok && name != i.name {
+			continue
+		}
+		if contains, ok := args["name_Icontains"].(string); ok && !strings.Contains(strings.ToLower(i.name), strings.ToLower(contains)) {
+			continue
+		}
+		if category, ok := args["category"].(string); ok && category != i.category {
+			continue
+		}
+		if labels, ok := args["labels_Contains"].([]interface{}); ok && !hasLabels(i.labels, labels) {
+			continue
+		}
+		nodes = append(nodes, i.object(s))
+	}
+	return s.page(nodes, args)
+}
+
+// hasLabels reports whether labels includes every one of want.
+func hasLabels(labels []string, want []interface{}) bool {
+	for _, w := range want {
+		found := false
+		for _, l := range labels {
+			if l == w {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) importIntegration(args map[string]interface{}) (interface{}, error) {
+	input := inputArg(args)
+	definition := stringArg(input, "definition")
+
+	var parsed struct {
+		Name        string   `yaml:"name"`
+		Description string   `yaml:"description"`
+		Category    string   `yaml:"category"`
+		Labels      []string `yaml:"labels"`
+		Flows       []struct {
+			Name          string `yaml:"name"`
+			Description   string `yaml:"description"`
+			IsSynchronous bool   `yaml:"isSynchronous"`
+		} `yaml:"flows"`
+	}
+	if err := yaml.Unmarshal([]byte(definition), &parsed); err != nil {
+		return payload("integration", nil, fieldError("definition", "Unable to parse the integration definition: "+err.Error())), nil
+	}
+	if parsed.Name == "" {
+		return payload("integration", nil, fieldError("definition", "The integration definition must have a name.")), nil
+	}
+
+	i := s.findIntegration(stringArg(input, "integrationId"))
+	if stringArg(input, "integrationId") != "" && (i == nil || i.root != i) {
+		return nil, errNotFound
+	}
+	if i == nil {
+		i = &integration{id: s.newId("Integration")}
+		i.root = i
+		s.integrations = append(s.integrations, i)
+	}
+	i.name = parsed.Name
+	i.description = parsed.Description
+	i.category = parsed.Category
+	i.labels = parsed.Labels
+	i.definition = definition
+	i.flows = nil
+	for _, f := range parsed.Flows {
+		id := s.newId("IntegrationFlow")
+		i.flows = append(i.flows, &integrationFlow{
+			id:            id,
+			name:          f.Name,
+			description:   f.Description,
+			isSynchronous: f.IsSynchronous,
+			testUrl:       s.URL + "/trigger/" + id,
+		})
+	}
+	return payload("integration", i.object(s)), nil
+}
+
+func (s *Server) publishIntegration(args map[string]interface{}) (interface{}, error) {
+	input := inputArg(args)
+	draft := s.findIntegration(stringArg(input, "id"))
+	if draft == nil || draft.root != draft {
+		return nil, errNotFound
+	}
+
+	latest := 0
+	for _, v := range s.versionsOf(draft) {
+		if v.versionNumber > latest {
+			latest = v.versionNumber
+		}
+	}
+	version := &integration{
+		id:               s.newId("Integration"),
+		root:             draft,
+		name:             draft.name,
+		description:      draft.description,
+		category:         draft.category,
+		labels:           draft.labels,
+		definition:       draft.definition,
+		flows:            draft.flows,
+		versionNumber:    latest + 1,
+		versionComment:   stringArg(input, "comments"),
+		versionCreatedAt: s.now(),
+		versionAvailable: true,
+	}
+	s.integrations = append(s.integrations, version)
+	return payload("integration", version.object(s)), nil
+}
+
+func (s *Server) deleteIntegration(args map[string]interface{}) (interface{}, error) {
+	draft := s.findIntegration(stringArg(inputArg(args), "id"))
+	if draft == nil || draft.root != draft {
+		return nil, errNotFound
+	}
+	for _, in := range s.instances {
+		if in.integration.root == draft {
+			return payload("integration", nil, fieldError("id", "This integration has instances and cannot be deleted.")), nil
+		}
+	}
+	remaining := s.integrations[:0]
+	for _, i := range s.integrations {
+		if i.root != draft {
+			remaining = append(remaining, i)
+		}
+	}
+	s.integrations = remaining
+	return payload("integration", draft.object(s)), nil
+}
+
+// Instances
+
+type instance struct {
+	id              string
+	name            string
+	description     string
+	customer        *customer
+	integration     *integration
+	configVariables map[string]string
+	deployedVersion int
+	lastDeployedAt  string
+	needsDeploy     bool
+}
+
+func (in *instance) object(s *Server) object {
+	keys := make([]string, 0, len(in.configVariables))
+	for k := range in.configVariables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	variables := make([]object, 0, len(keys))
+	for _, k := range keys {
+		variables = append(variables, object{
+			"value":                  in.configVariables[k],
+			"requiredConfigVariable": object{"key": k},
+		})
+	}
+
+	return object{
+		"id":              in.id,
+		"name":            in.name,
+		"description":     in.description,
+		"customer":        in.customer.object(),
+		"integration":     in.integration.object(s),
+		"configVariables": connection(variables),
+		"deployedVersion": in.deployedVersion,
+		"lastDeployedAt":  in.lastDeployedAt,
+		"needsDeploy":     in.needsDeploy,
+	}
+}
+
+func (s *Server) findInstance(id string) *instance {
+	for _, in := range s.instances {
+		if in.id == id {
+			return in
+		}
+	}
+	return nil
+}
+
+func (s *Server) instanceQuery(args map[string]interface{}) (interface{}, error) {
+	in := s.findInstance(stringArg(args, "id"))
+	if in == nil {
+		return nil, errNotFound
+	}
+	return in.object(s), nil
+}
+
+func configVariables(v interface{}) map[string]string {
+	items, _ := v.([]interface{})
+	values := make(map[string]string, len(items))
+	for _, item := range items {
+		if variable, ok := item.(map[string]interface{}); ok {
+			values[stringArg(variable, "key")] = stringArg(variable, "value")
+		}
+	}
+	return values
+}
+
+func (s *Server) createInstance(args map[string]interface{}) (interface{}, error) {
+	input := inputArg(args)
+	c := s.findCustomer(stringArg(input, "customer"))
+	if c == nil {
+		return payload("instance", nil, fieldError("customer", "Customer not found.")), nil
+	}
+	i := s.findIntegration(stringArg(input, "integration"))
+	if i == nil {
+		return payload("instance", nil, fieldError("integration", "Integration not found.")), nil
+	}
+
+	in := &instance{
+		id:              s.newId("Instance"),
+		name:            stringArg(input, "name"),
+		description:     stringArg(input, "description"),
+		customer:        c,
+		integration:     i,
+		configVariables: configVariables(input["configVariables"]),
+		needsDeploy:     true,
+	}
+	s.instances = append(s.instances, in)
+	return payload("instance", in.object(s)), nil
+}
+
+func (s *Server) updateInstance(args map[string]interface{}) (interface{}, error) {
+	input := inputArg(args)
+	in := s.findInstance(stringArg(input, "id"))
+	if in == nil {
+		return nil, errNotFound
+	}
+	if id, ok := optionalString(input, "integration"); ok && id != "" {
+		i := s.findIntegration(id)
+		if i == nil {
+			return payload("instance", nil, fieldError("integration", "Integration not found.")), nil
+		}
+		if i != in.integration {
+			in.integration = i
+			in.needsDeploy = true
+		}
+	}
+	if name, ok := optionalString(input, "name"); ok {
+		in.name = name
+	}
+	if description, ok := optionalString(input, "description"); ok {
+		in.description = description
+	}
+	if v, ok := input["configVariables"]; ok && v != nil {
+		for k, value := range configVariables(v) {
+			in.configVariables[k] = value
+		}
+		in.needsDeploy = true
+	}
+	return payload("instance", in.object(s)), nil
+}
+
+func (s *Server) deployInstance(args map[string]interface{}) (interface{}, error) {
+	in := s.findInstance(stringArg(inputArg(args), "id"))
+	if in == nil {
+		return nil, errNotFound
+	}
+	in.deployedVersion = in.integration.versionNumber
+	in.lastDeployedAt = s.now()
+	in.needsDeploy = false
+	return payload("instance", in.object(s)), nil
+}
+
+func (s *Server) deleteInstance(args map[string]interface{}) (interface{}, error) {
+	id := stringArg(inputArg(args), "id")
+	for i, in := range s.instances {
+		if in.id == id {
+			s.instances = append(s.instances[:i], s.instances[i+1:]...)
+			return payload("instance", in.object(s)), nil
+		}
+	}
+	return nil, errNotFound
+}
+
+// Components
+
+type component struct {
+	id               string
+	key              string
+	label            string
+	description      string
+	category         string
+	signature        string
+	public           bool
+	customerId       string
+	versionNumber    int
+	versionCreatedAt string
+	actions          []interface{}
+	connections      []interface{}
+}
+
+func (c *component) object() object {
+	var customer interface{}
+	if c.customerId != "" {
+		customer = object{"id": c.customerId}
+	}
+	return object{
+		"id":               c.id,
+		"key":              c.key,
+		"label":            c.label,
+		"description":      c.description,
+		"category":         c.category,
+		"signature":        c.signature,
+		"public":           c.public,
+		"versionNumber":    c.versionNumber,
+		"versionCreatedAt": c.versionCreatedAt,
+		"customer":         customer,
+		"actions":          connection(componentDefinitions(c.actions)),
+		"connections":      connection(componentDefinitions(c.connections)),
+	}
+}
+
+// componentDefinitions maps the actions or connections of a published component
+// definition, with their display and inputs, to the objects the API returns.
+func componentDefinitions(definitions []interface{}) []object {
+	nodes := []object{}
+	for _, d := range definitions {
+		definition, _ := d.(map[string]interface{})
+		display, _ := definition["display"].(map[string]interface{})
+		label := stringArg(display, "label")
+		if label == "" {
+			label = stringArg(definition, "label")
+		}
+		inputs := []object{}
+		list, _ := definition["inputs"].([]interface{})
+		for _, i := range list {
+			input, _ := i.(map[string]interface{})
+			required, _ := input["required"].(bool)
+			var def interface{}
+			if v, ok := input["default"]; ok && v != nil {
+				def = fmt.Sprint(v)
+			}
+			inputs = append(inputs, object{
+				"key":      stringArg(input, "key"),
+				"label":    stringArg(input, "label"),
+				"type":     stringArg(input, "type"),
+				"required": required,
+				"default":  def,
+			})
+		}
+		nodes = append(nodes, object{
+			"key":         stringArg(definition, "key"),
+			"label":       label,
+			"description": stringArg(display, "description"),
+			"inputs":      connection(inputs),
+		})
+	}
+	return nodes
+}
+
+// archiveComponentVersion keeps the version of c about to be replaced by a new
+// publish as a record of its own.
+func (s *Server) archiveComponentVersion(c *component) {
+	version := *c
+	version.id = s.newId("Component")
+	s.componentVersions = append(s.componentVersions, &version)
+}
+
+func (s *Server) findComponent(id string) *component {
+	for _, c := range s.components {
+		if c.id == id {
+			return c
+		}
+	}
+	for _, c := range s.componentVersions {
+		if c.id == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *Server) componentQuery(args map[string]interface{}) (interface{}, error) {
+	c := s.findComponent(stringArg(args, "id"))
+	if c == nil {
+		return nil, errNotFound
+	}
+	return c.object(), nil
+}
+
+func (s *Server) componentsQuery(args map[string]interface{}) (interface{}, error) {
+	components := s.components
+	if all, _ := args["allVersions"].(bool); all {
+		components = append(append([]*component{}, s.components...), s.componentVersions...)
+	}
+	var nodes []object
+	for _, c := range components {
+		if key, ok := args["key"].(string); ok && key != c.key {
+			continue
+		}
+		if public, ok := args["public"].(bool); ok && public != c.public {
+			continue
+		}
+		if contains, ok := args["key_Icontains"].(string); ok && !strings.Contains(strings.ToLower(c.key), strings.ToLower(contains)) {
+			continue
+		}
+		if contains, ok := args["label_Icontains"].(string); ok && !strings.Contains(strings.ToLower(c.label), strings.ToLower(contains)) {
+			continue
+		}
+		if category, ok := args["category"].(string); ok && category != c.category {
+			continue
+		}
+		nodes = append(nodes, c.object())
+	}
+	return s.page(nodes, args)
+}
+
+func (s *Server) publishComponent(args map[string]interface{}) (interface{}, error) {
+	input := inputArg(args)
+	definition, _ := input["definition"].(map[string]interface{})
+	display, _ := definition["display"].(map[string]interface{})
+	key := stringArg(definition, "key")
+	if key == "" {
+		return nil, fmt.Errorf("Variable \"$input\" got invalid value; field \"key\" of type DefinitionInput is required")
+	}
+
+	// Publishing upserts the organization's private Component with the same key.
+	var c *component
+	for _, existing := range s.components {
+		if existing.key == key && !existing.public {
+			c = existing
+		}
+	}
+	if c == nil {
+		c = &component{id: s.newId("Component"), key: key}
+		s.components = append(s.components, c)
+	} else {
+		s.archiveComponentVersion(c)
+	}
+	c.label = stringArg(display, "label")
+	c.description = stringArg(display, "description")
+	c.category = stringArg(display, "category")
+	c.signature = stringArg(input, "signature")
+	c.actions, _ = input["actions"].([]interface{})
+	c.connections, _ = definition["connections"].([]interface{})
+	c.versionNumber++
+	c.versionCreatedAt = s.now()
+
+	return object{
+		"publishResult": object{
+			"component":        c.object(),
+			"iconUploadUrl":    s.URL + "/uploads/" + c.id + "/icon",
+			"packageUploadUrl": s.URL + "/uploads/" + c.id + "/package",
+		},
+		"errors": []object{},
+	}, nil
+}
+
+// RepublishComponent publishes a new version of the private Component with the
+// given key and signature, as publishing it outside Terraform would.
+func (s *Server) RepublishComponent(key string, signature string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.components {
+		if c.key == key && !c.public {
+			s.archiveComponentVersion(c)
+			c.signature = signature
+			c.versionNumber++
+			c.versionCreatedAt = s.now()
+			return nil
+		}
+	}
+	return fmt.Errorf("no private component with key %q", key)
+}
+
+func (s *Server) deleteComponent(args map[string]interface{}) (interface{}, error) {
+	id := stringArg(inputArg(args), "id")
+	for i, c := range s.components {
+		if c.id == id {
+			s.components = append(s.components[:i], s.components[i+1:]...)
+			var versions []*component
+			for _, v := range s.componentVersions {
+				if v.key != c.key || v.public != c.public {
+					versions = append(versions, v)
+				}
+			}
+			s.componentVersions = versions
+			return payload("component", c.object()), nil
+		}
+	}
+	return nil, errNotFound
+}
+
+// Signing keys
+
+type signingKey struct {
+	id        string
+	publicKey string
+	imported  bool
+	issuedAt  string
+}
+
+func (k *signingKey) object() object {
+	return object{"id": k.id, "publicKey": k.publicKey, "imported": k.imported, "issuedAt": k.issuedAt}
+}
+
+func (s *Server) importOrganizationSigningKey(args map[string]interface{}) (interface{}, error) {
+	publicKey := stringArg(inputArg(args), "publicKey")
+	if !strings.HasPrefix(strings.TrimSpace(publicKey), "-----BEGIN ") {
+		return payload("organizationSigningKey", nil, fieldError("publicKey", "The public key must be PEM encoded.")), nil
+	}
+	k := &signingKey{id: s.newId("OrganizationSigningKey"), publicKey: publicKey, imported: true, issuedAt: s.now()}
+	s.signingKeys = append(s.signingKeys, k)
+	return payload("organizationSigningKey", k.object()), nil
+}
+
+func (s *Server) deleteOrganizationSigningKey(args map[string]interface{}) (interface{}, error) {
+	id := stringArg(inputArg(args), "id")
+	for i, k := range s.signingKeys {
+		if k.id == id {
+			s.signingKeys = append(s.signingKeys[:i], s.signingKeys[i+1:]...)
+			return payload("organizationSigningKey", k.object()), nil
+		}
+	}
+	return nil, errNotFound
+}
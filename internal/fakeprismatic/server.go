@@ -0,0 +1,306 @@
+// Package fakeprismatic is an in-memory stand-in for the Prismatic API, for running
+// the provider's resources and data sources in tests without a tenant or network.
+//
+// It serves the GraphQL endpoint at /api, the token exchange at /auth/refresh and
+// the signed upload URLs it hands out for component publishes, and implements the
+// queries and mutations the provider issues against in-memory integrations,
+// instances, customers, components, users, roles and signing keys. Only the
+// GraphQL subset that shurcooL/graphql generates is understood, and selecting a
+// field the fake does not model is an error, so a query the real API would reject
+// for an unknown field fails here too.
+package fakeprismatic
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is a running fake Prismatic API.
+type Server struct {
+	// URL is the base URL of the fake API, as given to the provider's url setting.
+	URL string
+	// Token is an access token the API accepts.
+	Token string
+	// RefreshToken can be exchanged at /auth/refresh for new access tokens.
+	RefreshToken string
+
+	httpServer *httptest.Server
+
+	mu      sync.Mutex
+	ids     map[string]int
+	clock   time.Time
+	tokens  map[string]bool
+	uploads map[string][]byte
+	// pageSize is the number of nodes a connection returns when a query does not
+	// ask for a number with first.
+	pageSize int
+
+	organization organization
+	roles        []*role
+	users        []*user
+	customers    []*customer
+	integrations []*integration
+	instances    []*instance
+	components   []*component
+	// componentVersions holds the earlier published versions of components, which
+	// the API keeps as records of their own.
+	componentVersions []*component
+	signingKeys       []*signingKey
+}
+
+// NewServer starts a fake API seeded with an organization, its roles and the
+// authenticated user. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		Token:        "fake-access-token",
+		RefreshToken: "fake-refresh-token",
+		ids:          map[string]int{},
+		pageSize:     100,
+		clock:        time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		tokens:       map[string]bool{},
+		uploads:      map[string][]byte{},
+	}
+	s.tokens[s.Token] = true
+	s.seed()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api", s.serveGraphQL)
+	mux.HandleFunc("/auth/refresh", s.serveRefresh)
+	mux.HandleFunc("/uploads/", s.serveUpload)
+	s.httpServer = httptest.NewServer(mux)
+	s.URL = s.httpServer.URL
+	return s
+}
+
+// SetPageSize sets the number of nodes a connection returns per page when a
+// query does not ask for a number with first.
+func (s *Server) SetPageSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = n
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Uploaded returns the content last uploaded to the upload URL with the given path
+// (such as "/uploads/<component id>/package"), if any.
+func (s *Server) Uploaded(path string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.uploads[path]
+	return content, ok
+}
+
+// newId returns a new opaque ID in the style of Prismatic's base64 global IDs.
+func (s *Server) newId(typeName string) string {
+	s.ids[typeName]++
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d", typeName, s.ids[typeName])))
+}
+
+// now returns the fake clock's time, advancing it a second per call so timestamps
+// are distinct and reproducible.
+func (s *Server) now() string {
+	s.clock = s.clock.Add(time.Second)
+	return s.clock.Format(time.RFC3339)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && s.tokens[token]
+}
+
+// gqlRequest is the body of a GraphQL request.
+type gqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// gqlError is an entry of a GraphQL response's top-level errors.
+type gqlError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+type gqlResponse struct {
+	Data   interface{} `json:"data"`
+	Errors []gqlError  `json:"errors,omitempty"`
+}
+
+func (s *Server) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req gqlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.execute(req))
+}
+
+func (s *Server) execute(req gqlRequest) gqlResponse {
+	op, err := parseOperation(req.Query)
+	if err != nil {
+		return gqlResponse{Errors: []gqlError{{Message: err.Error()}}}
+	}
+
+	resolvers := queryResolvers
+	if op.mutation {
+		resolvers = mutationResolvers
+	}
+
+	data := map[string]interface{}{}
+	var errs []gqlError
+	for _, f := range op.selections {
+		key := f.responseKey()
+		resolve, ok := resolvers[f.name]
+		if !ok {
+			errs = append(errs, gqlError{Message: fmt.Sprintf("Cannot query field %q on the root type", f.name), Path: []interface{}{key}})
+			data[key] = nil
+			continue
+		}
+
+		result, err := resolve(s, resolveArguments(f.arguments, req.Variables))
+		if err == nil {
+			result, err = project(result, f, req.Variables)
+		}
+		if err != nil {
+			errs = append(errs, gqlError{Message: err.Error(), Path: []interface{}{key}})
+			data[key] = nil
+			continue
+		}
+		data[key] = result
+	}
+
+	if len(errs) > 0 && len(errs) == len(op.selections) {
+		return gqlResponse{Data: nil, Errors: errs}
+	}
+	return gqlResponse{Data: data, Errors: errs}
+}
+
+// object is a resolved GraphQL object. Field values are scalars, objects, lists,
+// or resolverFuncs for fields that take arguments or would otherwise recurse.
+type object map[string]interface{}
+
+type resolverFunc func(args map[string]interface{}) (interface{}, error)
+
+// project narrows a resolved value to the selection set of f.
+func project(v interface{}, f *field, variables map[string]interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case object:
+		if len(f.selections) == 0 {
+			return nil, fmt.Errorf("field %q of object type must have a selection of subfields", f.name)
+		}
+		result := make(map[string]interface{}, len(f.selections))
+		for _, sel := range f.selections {
+			fv, ok := v[sel.name]
+			if !ok {
+				return nil, fmt.Errorf("Cannot query field %q on the type of field %q", sel.name, f.name)
+			}
+			if resolve, ok := fv.(resolverFunc); ok {
+				var err error
+				if fv, err = resolve(resolveArguments(sel.arguments, variables)); err != nil {
+					return nil, err
+				}
+			} else if len(sel.arguments) > 0 {
+				return nil, fmt.Errorf("field %q does not take arguments", sel.name)
+			}
+			projected, err := project(fv, sel, variables)
+			if err != nil {
+				return nil, err
+			}
+			result[sel.responseKey()] = projected
+		}
+		return result, nil
+	case []object:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			projected, err := project(item, f, variables)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = projected
+		}
+		return list, nil
+	default:
+		if len(f.selections) > 0 {
+			return nil, fmt.Errorf("field %q of scalar type cannot have a selection of subfields", f.name)
+		}
+		return v, nil
+	}
+}
+
+func (s *Server) serveRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.RefreshToken != s.RefreshToken {
+		http.Error(w, "invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+	s.ids["AccessToken"]++
+	token := fmt.Sprintf("fake-access-token-%d", s.ids["AccessToken"])
+	s.tokens[token] = true
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+func (s *Server) serveUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploads[r.URL.Path] = content
+	w.WriteHeader(http.StatusOK)
+}
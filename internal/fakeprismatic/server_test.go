@@ -0,0 +1,415 @@
+package fakeprismatic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shurcooL/graphql"
+)
+
+type bearerTransport struct {
+	token string
+}
+
+func (t bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestClient(t *testing.T) (*Server, *graphql.Client) {
+	t.Helper()
+	s := NewServer()
+	t.Cleanup(s.Close)
+	return s, graphql.NewClient(s.URL+"/api", &http.Client{Transport: bearerTransport{s.Token}})
+}
+
+func TestParseOperation(t *testing.T) {
+	op, err := parseOperation(`mutation($input:CreateCustomerInput!){createCustomer(input: $input){customer{id},errors{field,messages}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !op.mutation || len(op.selections) != 1 {
+		t.Fatalf("got %+v, want one mutation field", op)
+	}
+	f := op.selections[0]
+	if f.name != "createCustomer" || f.arguments["input"] != variableRef("input") || len(f.selections) != 2 {
+		t.Errorf("got %+v", f)
+	}
+
+	op, err = parseOperation(`query{first: users(customer_Isnull: true, email: "a\"b", first: 10, sort: [NAME]){nodes{id}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f = op.selections[0]
+	if f.responseKey() != "first" || f.name != "users" {
+		t.Errorf("got key %q name %q", f.responseKey(), f.name)
+	}
+	args := resolveArguments(f.arguments, nil)
+	want := map[string]interface{}{"customer_Isnull": true, "email": `a"b`, "first": float64(10), "sort": []interface{}{"NAME"}}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("got arguments %v, want %v", args, want)
+	}
+
+	for _, invalid := range []string{`{a`, `{...frag}`, `query{a(b:)}`, `{a}}`} {
+		if _, err := parseOperation(invalid); err == nil {
+			t.Errorf("parseOperation(%q) succeeded, want error", invalid)
+		}
+	}
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	resp, err := http.Post(s.URL+"/api", "application/json", strings.NewReader(`{"query":"{authenticatedUser{id}}"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got status %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestServerRefresh(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	body, _ := json.Marshal(map[string]string{"refresh_token": s.RefreshToken})
+	resp, err := http.Post(s.URL+"/auth/refresh", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var result struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+
+	client := graphql.NewClient(s.URL+"/api", &http.Client{Transport: bearerTransport{result.AccessToken}})
+	var query struct {
+		AuthenticatedUser struct {
+			Email string
+		}
+	}
+	if err := client.Query(context.Background(), &query, nil); err != nil {
+		t.Fatal(err)
+	}
+	if query.AuthenticatedUser.Email != "owner@example.com" {
+		t.Errorf("got email %q", query.AuthenticatedUser.Email)
+	}
+
+	resp, err = http.Post(s.URL+"/auth/refresh", "application/json", strings.NewReader(`{"refresh_token":"wrong"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got status %d for a bad refresh token, want 401", resp.StatusCode)
+	}
+}
+
+type CreateCustomerInput struct {
+	Name       graphql.String `json:"name"`
+	ExternalId graphql.String `json:"externalId,omitempty"`
+}
+
+type UpdateCustomerInput struct {
+	Id   graphql.ID      `json:"id"`
+	Name *graphql.String `json:"name,omitempty"`
+}
+
+func TestServerCustomerRoundTrip(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	var create struct {
+		CreateCustomer struct {
+			Customer struct {
+				Id string
+			}
+			Errors []struct {
+				Field    string
+				Messages []string
+			}
+		} `graphql:"createCustomer(input: $input)"`
+	}
+	input := CreateCustomerInput{Name: "Acme", ExternalId: "acme"}
+	if err := client.Mutate(ctx, &create, map[string]interface{}{"input": input}); err != nil {
+		t.Fatal(err)
+	}
+	id := create.CreateCustomer.Customer.Id
+	if id == "" || len(create.CreateCustomer.Errors) != 0 {
+		t.Fatalf("got %+v", create.CreateCustomer)
+	}
+
+	// A duplicate external ID is a field error, not a GraphQL error.
+	if err := client.Mutate(ctx, &create, map[string]interface{}{"input": CreateCustomerInput{Name: "Other", ExternalId: "acme"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(create.CreateCustomer.Errors) != 1 || create.CreateCustomer.Errors[0].Field != "externalId" {
+		t.Errorf("got errors %+v, want one on externalId", create.CreateCustomer.Errors)
+	}
+
+	var update struct {
+		UpdateCustomer struct {
+			Customer struct {
+				Id string
+			}
+		} `graphql:"updateCustomer(input: $input)"`
+	}
+	name := graphql.String("Acme Corp")
+	if err := client.Mutate(ctx, &update, map[string]interface{}{"input": UpdateCustomerInput{Id: id, Name: &name}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var query struct {
+		Customer struct {
+			Name       string
+			ExternalId string
+		} `graphql:"customer(id: $id)"`
+	}
+	if err := client.Query(ctx, &query, map[string]interface{}{"id": graphql.ID(id)}); err != nil {
+		t.Fatal(err)
+	}
+	if query.Customer.Name != "Acme Corp" || query.Customer.ExternalId != "acme" {
+		t.Errorf("got %+v", query.Customer)
+	}
+
+	err := client.Query(ctx, &query, map[string]interface{}{"id": graphql.ID("missing")})
+	if err == nil || err.Error() != "Record not found" {
+		t.Errorf("got error %v for a missing customer, want Record not found", err)
+	}
+}
+
+func TestServerRejectsUnknownFields(t *testing.T) {
+	_, client := newTestClient(t)
+
+	var query struct {
+		AuthenticatedUser struct {
+			Nickname string
+		}
+	}
+	if err := client.Query(context.Background(), &query, nil); err == nil {
+		t.Error("querying an unmodeled field succeeded, want error")
+	}
+}
+
+type ImportIntegrationInput struct {
+	IntegrationId graphql.ID     `json:"integrationId,omitempty"`
+	Definition    graphql.String `json:"definition"`
+}
+
+type PublishIntegrationInput struct {
+	Id graphql.ID `json:"id"`
+}
+
+func TestServerIntegrationVersions(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	var imported struct {
+		ImportIntegration struct {
+			Integration struct {
+				Id string
+			}
+		} `graphql:"importIntegration(input: $input)"`
+	}
+	if err := client.Mutate(ctx, &imported, map[string]interface{}{"input": ImportIntegrationInput{Definition: "name: Test\ndescription: A test\n"}}); err != nil {
+		t.Fatal(err)
+	}
+	draft := imported.ImportIntegration.Integration.Id
+
+	var published struct {
+		PublishIntegration struct {
+			Integration struct {
+				Id            string
+				VersionNumber int
+			}
+		} `graphql:"publishIntegration(input: $input)"`
+	}
+	for i := 1; i <= 2; i++ {
+		if err := client.Mutate(ctx, &published, map[string]interface{}{"input": PublishIntegrationInput{Id: draft}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var query struct {
+		Integration struct {
+			Name            string
+			VersionSequence struct {
+				Nodes []struct {
+					VersionNumber int
+				}
+			}
+		} `graphql:"integration(id: $id)"`
+	}
+	if err := client.Query(ctx, &query, map[string]interface{}{"id": graphql.ID(published.PublishIntegration.Integration.Id)}); err != nil {
+		t.Fatal(err)
+	}
+	var versions []int
+	for _, v := range query.Integration.VersionSequence.Nodes {
+		versions = append(versions, v.VersionNumber)
+	}
+	if query.Integration.Name != "Test" || !reflect.DeepEqual(versions, []int{0, 2, 1}) {
+		t.Errorf("got name %q versions %v, want Test [0 2 1]", query.Integration.Name, versions)
+	}
+}
+
+func TestServerPagination(t *testing.T) {
+	s, client := newTestClient(t)
+	ctx := context.Background()
+	s.SetPageSize(2)
+
+	for _, name := range []string{"Alpha", "Beta", "Gamma"} {
+		var imported struct {
+			ImportIntegration struct {
+				Integration struct {
+					Id string
+				}
+			} `graphql:"importIntegration(input: $input)"`
+		}
+		definition := fmt.Sprintf("name: %s\nlabels: [team, %s]\n", name, strings.ToLower(name))
+		if err := client.Mutate(ctx, &imported, map[string]interface{}{"input": ImportIntegrationInput{Definition: graphql.String(definition)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var names []string
+	variables := map[string]interface{}{
+		"after":  (*graphql.String)(nil),
+		"labels": []graphql.String{"team"},
+	}
+	for pages := 1; ; pages++ {
+		var query struct {
+			Integrations struct {
+				Nodes []struct {
+					Name string
+				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   *graphql.String
+				}
+			} `graphql:"integrations(after: $after, labels_Contains: $labels)"`
+		}
+		if err := client.Query(ctx, &query, variables); err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range query.Integrations.Nodes {
+			names = append(names, n.Name)
+		}
+		if !query.Integrations.PageInfo.HasNextPage {
+			if pages != 2 {
+				t.Errorf("got %d pages, want 2", pages)
+			}
+			break
+		}
+		variables["after"] = query.Integrations.PageInfo.EndCursor
+	}
+	if !reflect.DeepEqual(names, []string{"Alpha", "Beta", "Gamma"}) {
+		t.Errorf("got %v, want every integration once", names)
+	}
+
+	var filtered struct {
+		Integrations struct {
+			Nodes []struct {
+				Name string
+			}
+		} `graphql:"integrations(labels_Contains: $labels)"`
+	}
+	if err := client.Query(ctx, &filtered, map[string]interface{}{"labels": []graphql.String{"team", "beta"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered.Integrations.Nodes) != 1 || filtered.Integrations.Nodes[0].Name != "Beta" {
+		t.Errorf("got %+v, want only Beta", filtered.Integrations.Nodes)
+	}
+}
+
+type PublishComponentInput struct {
+	Definition map[string]interface{} `json:"definition"`
+	Actions    []interface{}          `json:"actions"`
+	Signature  graphql.String         `json:"signature"`
+}
+
+func TestServerComponentVersions(t *testing.T) {
+	s, client := newTestClient(t)
+	ctx := context.Background()
+
+	var published struct {
+		PublishComponent struct {
+			PublishResult struct {
+				Component struct {
+					Id string
+				}
+			}
+		} `graphql:"publishComponent(input: $input)"`
+	}
+	input := PublishComponentInput{Definition: map[string]interface{}{"key": "example"}, Actions: []interface{}{}, Signature: "first"}
+	if err := client.Mutate(ctx, &published, map[string]interface{}{"input": input}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RepublishComponent("example", "second"); err != nil {
+		t.Fatal(err)
+	}
+
+	type components struct {
+		Nodes []struct {
+			Id            string
+			Signature     string
+			VersionNumber int
+		}
+	}
+	var latest struct {
+		Components components `graphql:"components(key: $key)"`
+	}
+	var all struct {
+		Components components `graphql:"components(key: $key, allVersions: true)"`
+	}
+	variables := map[string]interface{}{"key": graphql.String("example")}
+	if err := client.Query(ctx, &latest, variables); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Query(ctx, &all, variables); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(latest.Components.Nodes) != 1 || latest.Components.Nodes[0].VersionNumber != 2 || latest.Components.Nodes[0].Id != published.PublishComponent.PublishResult.Component.Id {
+		t.Errorf("got latest %+v, want version 2 under the published id", latest.Components.Nodes)
+	}
+	var versions []string
+	for _, c := range all.Components.Nodes {
+		versions = append(versions, fmt.Sprintf("%d:%s", c.VersionNumber, c.Signature))
+	}
+	if !reflect.DeepEqual(versions, []string{"2:second", "1:first"}) {
+		t.Errorf("got versions %v, want [2:second 1:first]", versions)
+	}
+}
+
+func TestServerUploads(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, s.URL+"/uploads/abc/package", strings.NewReader("zip bytes"))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	content, ok := s.Uploaded("/uploads/abc/package")
+	if !ok || string(content) != "zip bytes" {
+		t.Errorf("got %q, %v", content, ok)
+	}
+	if err := s.RepublishComponent("missing", "sig"); err == nil {
+		t.Error("republishing an unknown component succeeded, want error")
+	}
+}
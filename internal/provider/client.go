@@ -2,11 +2,15 @@ package provider
 
 import (
 	"bytes"
-	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/shurcooL/graphql"
 	"golang.org/x/oauth2"
@@ -17,33 +21,132 @@ type RefreshTokenRequest struct {
 	TenantId     *string `json:"tenant_id,omitempty"`
 }
 
-// newGraphQLClient builds an authenticated GraphQL client for the Prismatic API.
-// When a refresh token is supplied it is first exchanged for an access token.
-func newGraphQLClient(baseUrl, token, refreshToken, tenantId string) (*graphql.Client, error) {
+// newGraphQLClient builds an authenticated GraphQL client for the Prismatic API that
+// sends its requests through transport. When a refresh token is supplied it is
+// exchanged for an access token up front, and again whenever that access token
+// expires or is rejected.
+func newGraphQLClient(baseUrl, token, refreshToken, tenantId string, transport http.RoundTripper) (*graphql.Client, error) {
 	u, err := url.Parse(baseUrl)
 	if err != nil {
 		return nil, err
 	}
 
+	var src oauth2.TokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 	if refreshToken != "" {
 		var tid *string
 		if tenantId != "" {
 			tid = &tenantId
 		}
-		accessToken, err := refreshAccessToken(u, RefreshTokenRequest{RefreshToken: refreshToken, TenantId: tid})
-		if err != nil {
+		refreshing := &refreshTokenSource{
+			baseUrl: *u,
+			request: RefreshTokenRequest{RefreshToken: refreshToken, TenantId: tid},
+		}
+		// Exchange eagerly so a bad refresh token fails provider configuration
+		// rather than the first resource operation.
+		if _, err := refreshing.Token(); err != nil {
 			return nil, err
 		}
-		token = *accessToken
+		src = refreshing
 	}
 
 	u.Path = "api"
-	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	httpClient := oauth2.NewClient(context.Background(), src)
+	httpClient := &http.Client{
+		Transport: &authTransport{source: src, base: transport},
+	}
 	return graphql.NewClient(u.String(), httpClient), nil
 }
 
-func refreshAccessToken(baseUrl *url.URL, refreshToken RefreshTokenRequest) (*string, error) {
+// refreshTokenSource is an oauth2.TokenSource that exchanges a refresh token for
+// access tokens, caching each one until it expires or is invalidated.
+type refreshTokenSource struct {
+	baseUrl url.URL
+	request RefreshTokenRequest
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func (s *refreshTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.Valid() {
+		return s.token, nil
+	}
+
+	token, err := refreshAccessToken(s.baseUrl, s.request)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// invalidate discards stale if it is still the cached token, so the next call to
+// Token exchanges the refresh token again. Comparing against the rejected token
+// keeps concurrent requests that all saw the same 401 from refreshing repeatedly.
+func (s *refreshTokenSource) invalidate(stale *oauth2.Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == stale {
+		s.token = nil
+	}
+}
+
+// authTransport authorizes each request with the current access token. If the API
+// rejects the token with a 401 and the source can renew it, the request is retried
+// once with a freshly exchanged token.
+type authTransport struct {
+	source oauth2.TokenSource
+	base   http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(authorizedRequest(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	refreshing, ok := t.source.(*refreshTokenSource)
+	if !ok || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	refreshing.invalidate(token)
+	fresh, err := refreshing.Token()
+	if err != nil {
+		return resp, nil
+	}
+
+	retry := authorizedRequest(req, fresh)
+	if req.Body != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return t.base.RoundTrip(retry)
+}
+
+// authorizedRequest returns a copy of req carrying token, leaving req untouched as
+// the RoundTripper contract requires.
+func authorizedRequest(req *http.Request, token *oauth2.Token) *http.Request {
+	r := req.Clone(req.Context())
+	token.SetAuthHeader(r)
+	return r
+}
+
+func refreshAccessToken(baseUrl url.URL, refreshToken RefreshTokenRequest) (*oauth2.Token, error) {
 	baseUrl.Path = "/auth/refresh"
 	apiUrl := baseUrl.String()
 
@@ -64,10 +167,41 @@ func refreshAccessToken(baseUrl *url.URL, refreshToken RefreshTokenRequest) (*st
 
 	var result struct {
 		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 
-	return &result.AccessToken, nil
+	token := &oauth2.Token{
+		AccessToken: result.AccessToken,
+		TokenType:   result.TokenType,
+		Expiry:      accessTokenExpiry(result.AccessToken),
+	}
+	if result.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// accessTokenExpiry reads the exp claim of a JWT access token, for refresh
+// responses that omit expires_in. It returns the zero time (never expires) when
+// the token is not a JWT, in which case a 401 is what triggers a refresh.
+func accessTokenExpiry(accessToken string) time.Time {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
 }
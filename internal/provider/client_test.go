@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/graphql"
+)
+
+// newRefreshTestServer serves /auth/refresh, handing out numbered access tokens
+// with the given lifetime, and /api, which rejects every token in rejected.
+func newRefreshTestServer(t *testing.T, expiresIn int64, rejected map[string]bool) (*httptest.Server, *int32) {
+	t.Helper()
+	var refreshes int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&refreshes, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   expiresIn,
+		})
+	})
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		if rejected[r.Header.Get("Authorization")] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"authenticatedUser":{"email":"` + r.Header.Get("Authorization") + `"}}}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &refreshes
+}
+
+func queryAuthorization(t *testing.T, client *graphql.Client) string {
+	t.Helper()
+	var query struct {
+		AuthenticatedUser struct {
+			Email string
+		}
+	}
+	if err := client.Query(context.Background(), &query, nil); err != nil {
+		t.Fatalf("query failed: %s", err)
+	}
+	return query.AuthenticatedUser.Email
+}
+
+func TestRefreshTokenSourceRenewsExpiredTokens(t *testing.T) {
+	// A lifetime inside oauth2's expiry margin makes every token already stale.
+	server, refreshes := newRefreshTestServer(t, 1, nil)
+
+	client, err := newGraphQLClient(server.URL, "", "refresh", "", http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("newGraphQLClient: %s", err)
+	}
+
+	if got := queryAuthorization(t, client); got != "Bearer token-2" {
+		t.Errorf("Authorization = %q, want the renewed token-2", got)
+	}
+	if n := atomic.LoadInt32(refreshes); n != 2 {
+		t.Errorf("refreshes = %d, want 2", n)
+	}
+}
+
+func TestRefreshTokenSourceCachesValidTokens(t *testing.T) {
+	server, refreshes := newRefreshTestServer(t, 3600, nil)
+
+	client, err := newGraphQLClient(server.URL, "", "refresh", "", http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("newGraphQLClient: %s", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if got := queryAuthorization(t, client); got != "Bearer token-1" {
+			t.Errorf("Authorization = %q, want the cached token-1", got)
+		}
+	}
+	if n := atomic.LoadInt32(refreshes); n != 1 {
+		t.Errorf("refreshes = %d, want 1", n)
+	}
+}
+
+func TestAuthTransportRetriesOnceOnUnauthorized(t *testing.T) {
+	server, refreshes := newRefreshTestServer(t, 3600, map[string]bool{"Bearer token-1": true})
+
+	client, err := newGraphQLClient(server.URL, "", "refresh", "", http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("newGraphQLClient: %s", err)
+	}
+
+	if got := queryAuthorization(t, client); got != "Bearer token-2" {
+		t.Errorf("Authorization = %q, want the re-exchanged token-2", got)
+	}
+	if n := atomic.LoadInt32(refreshes); n != 2 {
+		t.Errorf("refreshes = %d, want 2", n)
+	}
+}
+
+func TestAuthTransportDoesNotRetryStaticTokens(t *testing.T) {
+	server, refreshes := newRefreshTestServer(t, 3600, map[string]bool{"Bearer static": true})
+
+	client, err := newGraphQLClient(server.URL, "static", "", "", http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("newGraphQLClient: %s", err)
+	}
+
+	var query struct {
+		AuthenticatedUser struct {
+			Email string
+		}
+	}
+	if err := client.Query(context.Background(), &query, nil); err == nil {
+		t.Errorf("expected the 401 to surface as an error")
+	}
+	if n := atomic.LoadInt32(refreshes); n != 0 {
+		t.Errorf("refreshes = %d, want 0", n)
+	}
+}
+
+func TestRefreshAccessTokenLeavesBaseUrlUntouched(t *testing.T) {
+	server, _ := newRefreshTestServer(t, 3600, nil)
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := refreshAccessToken(*u, RefreshTokenRequest{RefreshToken: "refresh"}); err != nil {
+		t.Fatalf("refreshAccessToken: %s", err)
+	}
+	if u.Path != "" {
+		t.Errorf("base URL path = %q, want it unchanged", u.Path)
+	}
+}
+
+func TestAccessTokenExpiry(t *testing.T) {
+	exp := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+
+	if got := accessTokenExpiry("header." + payload + ".signature"); !got.Equal(exp) {
+		t.Errorf("accessTokenExpiry(jwt) = %s, want %s", got, exp)
+	}
+	if got := accessTokenExpiry("opaque-token"); !got.IsZero() {
+		t.Errorf("accessTokenExpiry(opaque) = %s, want zero", got)
+	}
+}
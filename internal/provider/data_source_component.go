@@ -0,0 +1,374 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+)
+
+var (
+	_ datasource.DataSource              = (*componentDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*componentDataSource)(nil)
+)
+
+type componentDataSource struct {
+	client *graphql.Client
+}
+
+type componentDataSourceModel struct {
+	Id                  types.String               `tfsdk:"id"`
+	Key                 types.String               `tfsdk:"key"`
+	Public              types.Bool                 `tfsdk:"public"`
+	VersionNumber       types.Int64                `tfsdk:"version_number"`
+	LatestVersionNumber types.Int64                `tfsdk:"latest_version_number"`
+	Label               types.String               `tfsdk:"label"`
+	Description         types.String               `tfsdk:"description"`
+	VersionCreatedAt    types.String               `tfsdk:"version_created_at"`
+	Signature           types.String               `tfsdk:"signature"`
+	CustomerId          types.String               `tfsdk:"customer_id"`
+	Actions             []componentActionModel     `tfsdk:"actions"`
+	Connections         []componentConnectionModel `tfsdk:"connections"`
+}
+
+type componentActionModel struct {
+	Key         types.String          `tfsdk:"key"`
+	Label       types.String          `tfsdk:"label"`
+	Description types.String          `tfsdk:"description"`
+	Inputs      []componentInputModel `tfsdk:"inputs"`
+}
+
+type componentConnectionModel struct {
+	Key    types.String          `tfsdk:"key"`
+	Label  types.String          `tfsdk:"label"`
+	Inputs []componentInputModel `tfsdk:"inputs"`
+}
+
+type componentInputModel struct {
+	Key      types.String `tfsdk:"key"`
+	Label    types.String `tfsdk:"label"`
+	Type     types.String `tfsdk:"type"`
+	Required types.Bool   `tfsdk:"required"`
+	Default  types.String `tfsdk:"default"`
+}
+
+// componentInputNodes is the selection of an action's or connection's inputs.
+type componentInputNodes struct {
+	Nodes []struct {
+		Key      graphql.String
+		Label    graphql.String
+		Type     graphql.String
+		Required graphql.Boolean
+		Default  *graphql.String
+	}
+}
+
+func (n componentInputNodes) models() []componentInputModel {
+	inputs := make([]componentInputModel, 0, len(n.Nodes))
+	for _, node := range n.Nodes {
+		def := types.StringNull()
+		if node.Default != nil {
+			def = types.StringValue(string(*node.Default))
+		}
+		inputs = append(inputs, componentInputModel{
+			Key:      types.StringValue(string(node.Key)),
+			Label:    types.StringValue(string(node.Label)),
+			Type:     types.StringValue(string(node.Type)),
+			Required: types.BoolValue(bool(node.Required)),
+			Default:  def,
+		})
+	}
+	return inputs
+}
+
+func (d *componentDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_component"
+}
+
+func (d *componentDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	inputs := schema.ListNestedAttribute{
+		Computed:    true,
+		Description: "The inputs it takes.",
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"key": schema.StringAttribute{
+					Computed:    true,
+					Description: "The key of the input",
+				},
+				"label": schema.StringAttribute{
+					Computed:    true,
+					Description: "The label of the input",
+				},
+				"type": schema.StringAttribute{
+					Computed:    true,
+					Description: "The type of the input, such as string or password",
+				},
+				"required": schema.BoolAttribute{
+					Computed:    true,
+					Description: "Whether the input must be given a value",
+				},
+				"default": schema.StringAttribute{
+					Computed:    true,
+					Description: "The default value of the input, if any",
+				},
+			},
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Data source to look up a Prismatic Component by key.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the Component version",
+			},
+			"key": schema.StringAttribute{
+				Required:    true,
+				Description: "The key of the Component.",
+			},
+			"public": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether to look up the public Component with the key or the Organization's private one. Required only when there are both.",
+			},
+			"version_number": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The version of the Component to look up. Defaults to the latest version.",
+			},
+			"latest_version_number": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The latest version number of the Component",
+			},
+			"label": schema.StringAttribute{
+				Computed:    true,
+				Description: "The label of the Component",
+			},
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "The description of the Component",
+			},
+			"version_created_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "When the version was published",
+			},
+			"signature": schema.StringAttribute{
+				Computed:    true,
+				Description: "The checksum of the bundle the version was published from",
+			},
+			"customer_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the Customer the Component belongs to, or null for a Component of the Organization",
+			},
+			"actions": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The actions of the Component version.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							Computed:    true,
+							Description: "The key of the action",
+						},
+						"label": schema.StringAttribute{
+							Computed:    true,
+							Description: "The label of the action",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "The description of the action",
+						},
+						"inputs": inputs,
+					},
+				},
+			},
+			"connections": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The connections of the Component version.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							Computed:    true,
+							Description: "The key of the connection",
+						},
+						"label": schema.StringAttribute{
+							Computed:    true,
+							Description: "The label of the connection",
+						},
+						"inputs": inputs,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *componentDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *componentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config componentDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, latest := d.findVersion(ctx, config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var query struct {
+		Component struct {
+			Id               graphql.ID
+			Key              graphql.String
+			Label            graphql.String
+			Description      graphql.String
+			Public           graphql.Boolean
+			VersionNumber    graphql.Int
+			VersionCreatedAt graphql.String
+			Signature        graphql.String
+			Customer         struct {
+				Id graphql.ID
+			}
+			Actions struct {
+				Nodes []struct {
+					Key         graphql.String
+					Label       graphql.String
+					Description graphql.String
+					Inputs      componentInputNodes
+				}
+			}
+			Connections struct {
+				Nodes []struct {
+					Key    graphql.String
+					Label  graphql.String
+					Inputs componentInputNodes
+				}
+			}
+		} `graphql:"component(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id": graphql.ID(id),
+	}
+	if err := d.client.Query(ctx, &query, variables); err != nil {
+		resp.Diagnostics.AddError("Unable to read component", err.Error())
+		return
+	}
+
+	component := query.Component
+	state := componentDataSourceModel{
+		Id:                  types.StringValue(component.Id.(string)),
+		Key:                 types.StringValue(string(component.Key)),
+		Public:              types.BoolValue(bool(component.Public)),
+		VersionNumber:       types.Int64Value(int64(component.VersionNumber)),
+		LatestVersionNumber: types.Int64Value(latest),
+		Label:               types.StringValue(string(component.Label)),
+		Description:         types.StringValue(string(component.Description)),
+		VersionCreatedAt:    types.StringValue(string(component.VersionCreatedAt)),
+		Signature:           types.StringValue(string(component.Signature)),
+		CustomerId:          types.StringNull(),
+		Actions:             make([]componentActionModel, 0, len(component.Actions.Nodes)),
+		Connections:         make([]componentConnectionModel, 0, len(component.Connections.Nodes)),
+	}
+	if customerId, ok := component.Customer.Id.(string); ok {
+		state.CustomerId = types.StringValue(customerId)
+	}
+	for _, action := range component.Actions.Nodes {
+		state.Actions = append(state.Actions, componentActionModel{
+			Key:         types.StringValue(string(action.Key)),
+			Label:       types.StringValue(string(action.Label)),
+			Description: types.StringValue(string(action.Description)),
+			Inputs:      action.Inputs.models(),
+		})
+	}
+	for _, connection := range component.Connections.Nodes {
+		state.Connections = append(state.Connections, componentConnectionModel{
+			Key:    types.StringValue(string(connection.Key)),
+			Label:  types.StringValue(string(connection.Label)),
+			Inputs: connection.Inputs.models(),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// findVersion returns the id of the Component version config selects and the
+// latest version number of that Component, recording diagnostics when none or
+// more than one Component matches.
+func (d *componentDataSource) findVersion(ctx context.Context, config componentDataSourceModel, diags *diag.Diagnostics) (string, int64) {
+	type versionNode struct {
+		Id            graphql.ID
+		Public        graphql.Boolean
+		VersionNumber graphql.Int
+	}
+	var nodes []versionNode
+	variables := map[string]interface{}{
+		"key":   graphql.String(config.Key.ValueString()),
+		"after": (*graphql.String)(nil),
+	}
+	for {
+		var query struct {
+			Components struct {
+				Nodes    []versionNode
+				PageInfo pageInfo
+			} `graphql:"components(key: $key, allVersions: true, after: $after)"`
+		}
+		if err := d.client.Query(ctx, &query, variables); err != nil {
+			diags.AddError("Unable to read component", err.Error())
+			return "", 0
+		}
+		nodes = append(nodes, query.Components.Nodes...)
+		if !query.Components.PageInfo.HasNextPage || query.Components.PageInfo.EndCursor == nil {
+			break
+		}
+		variables["after"] = query.Components.PageInfo.EndCursor
+	}
+
+	key := config.Key.ValueString()
+	publicity := map[bool]bool{}
+	var latest int64
+	for _, node := range nodes {
+		if !config.Public.IsNull() && bool(node.Public) != config.Public.ValueBool() {
+			continue
+		}
+		publicity[bool(node.Public)] = true
+		if int64(node.VersionNumber) > latest {
+			latest = int64(node.VersionNumber)
+		}
+	}
+	switch {
+	case len(publicity) == 0:
+		kind := "component"
+		if !config.Public.IsNull() {
+			kind = map[bool]string{true: "public component", false: "private component"}[config.Public.ValueBool()]
+		}
+		diags.AddError("Component not found", fmt.Sprintf("No %s has the key %q.", kind, key))
+		return "", 0
+	case len(publicity) > 1:
+		diags.AddError(
+			"Ambiguous component key",
+			fmt.Sprintf("Both a public and a private component have the key %q. Set public to choose one.", key),
+		)
+		return "", 0
+	}
+
+	version := latest
+	if !config.VersionNumber.IsNull() {
+		version = config.VersionNumber.ValueInt64()
+	}
+	for _, node := range nodes {
+		if !config.Public.IsNull() && bool(node.Public) != config.Public.ValueBool() {
+			continue
+		}
+		if int64(node.VersionNumber) == version {
+			return node.Id.(string), latest
+		}
+	}
+	diags.AddError("Component version not found", fmt.Sprintf("The component %q has no version %d; its latest is %d.", key, version, latest))
+	return "", 0
+}
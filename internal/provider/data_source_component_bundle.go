@@ -23,7 +23,10 @@ type componentBundleModel struct {
 	Id              types.String `tfsdk:"id"`
 	BundleDirectory types.String `tfsdk:"bundle_directory"`
 	BundlePath      types.String `tfsdk:"bundle_path"`
+	Include         types.List   `tfsdk:"include"`
+	Exclude         types.List   `tfsdk:"exclude"`
 	Signature       types.String `tfsdk:"signature"`
+	FileHashes      types.Map    `tfsdk:"file_hashes"`
 }
 
 func (d *componentBundleDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -45,9 +48,24 @@ func (d *componentBundleDataSource) Schema(ctx context.Context, req datasource.S
 				Required:    true,
 				Description: "Destination of the generated bundle",
 			},
+			"include": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Glob patterns, relative to `bundle_directory`, of the files to bundle. `**` matches any number of directories, and a pattern matching a directory includes everything beneath it. If omitted, all files are bundled.",
+			},
+			"exclude": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Glob patterns, relative to `bundle_directory`, of files to leave out of the bundle, such as `**/*.map` or `**/node_modules/.cache`. Exclusions take precedence over `include`. Paths listed in a `.prismaticignore` file (gitignore syntax) at the root of `bundle_directory` are left out as well.",
+			},
 			"signature": schema.StringAttribute{
 				Computed:    true,
-				Description: "Signature of the bundle for detecting redundant publishes",
+				Description: "Signature of the bundle for detecting redundant publishes. It is the SHA-256 of a manifest of each bundled file's path and SHA-256, so it changes only when the bundled files do.",
+			},
+			"file_hashes": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "SHA-256 of each bundled file, keyed by its path relative to `bundle_directory`",
 			},
 		},
 	}
@@ -67,17 +85,37 @@ func (d *componentBundleDataSource) Read(ctx context.Context, req datasource.Rea
 	bundleDirectory := config.BundleDirectory.ValueString()
 	bundlePath := config.BundlePath.ValueString()
 
-	_, packageSignature, err := util.GenerateBundleSignature(bundleDirectory, bundlePath)
+	var filter util.BundleFilter
+	resp.Diagnostics.Append(stringsFromList(ctx, config.Include, &filter.Include)...)
+	resp.Diagnostics.Append(stringsFromList(ctx, config.Exclude, &filter.Exclude)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := filter.Validate(); err != nil {
+		resp.Diagnostics.AddError("Invalid bundle pattern", err.Error())
+		return
+	}
+
+	bundle, err := util.GenerateBundleSignature(bundleDirectory, bundlePath, filter)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to generate bundle signature", err.Error())
 		return
 	}
 
+	fileHashes, diags := types.MapValueFrom(ctx, types.StringType, bundle.FileHashes)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	state := componentBundleModel{
 		Id:              types.StringValue(bundlePath),
 		BundleDirectory: config.BundleDirectory,
 		BundlePath:      config.BundlePath,
-		Signature:       types.StringValue(packageSignature),
+		Include:         config.Include,
+		Exclude:         config.Exclude,
+		Signature:       types.StringValue(bundle.Signature),
+		FileHashes:      fileHashes,
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
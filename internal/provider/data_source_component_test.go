@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const (
+	componentDataSourceName = "data.prismatic_component.component"
+	componentLookupConfig   = `
+data "prismatic_component_bundle" "bundle" {
+    bundle_directory = "../../test/data/component/code"
+    bundle_path = "../../test/data/component/bundle.zip"
+}
+
+resource "prismatic_component" "component" {
+    bundle_directory = data.prismatic_component_bundle.bundle.bundle_directory
+    bundle_path = data.prismatic_component_bundle.bundle.bundle_path
+    signature = data.prismatic_component_bundle.bundle.signature
+    manifest_path = "component.json"
+    deletion_policy = "delete"
+}
+
+data "prismatic_component" "component" {
+    key = prismatic_component.component.key
+    public = false
+    depends_on = [prismatic_component.component]
+}`
+)
+
+func TestAccDataSourceComponent_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: componentLookupConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(componentDataSourceName, "id", "prismatic_component.component", "id"),
+					resource.TestCheckResourceAttrPair(componentDataSourceName, "version_number", "prismatic_component.component", "version_number"),
+					resource.TestCheckResourceAttrPair(componentDataSourceName, "latest_version_number", "prismatic_component.component", "version_number"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "label", "Component label"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "actions.#", "1"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "actions.0.key", "actionKey"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "actions.0.label", "Action label"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "actions.0.inputs.0.key", "inputKey"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "actions.0.inputs.0.type", "string"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestUnitDataSourceComponent_lookup(t *testing.T) {
+	server := testUnitPreCheck(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testUnitCheckComponentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: componentLookupConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(componentDataSourceName, "id", "prismatic_component.component", "id"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "version_number", "1"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "public", "false"),
+					resource.TestCheckNoResourceAttr(componentDataSourceName, "customer_id"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "actions.0.key", "actionKey"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "actions.0.description", "Action description"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "actions.0.inputs.0.label", "Input label"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "actions.0.inputs.0.required", "false"),
+					resource.TestCheckNoResourceAttr(componentDataSourceName, "actions.0.inputs.0.default"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "connections.#", "0"),
+				),
+			},
+			// An earlier version is looked up by number once a newer one is published.
+			{
+				PreConfig: func() {
+					if err := server.RepublishComponent("componentKey", "published-elsewhere"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: strings.Replace(componentLookupConfig, "public = false", "public = false\n    version_number = 2", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(componentDataSourceName, "version_number", "2"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "signature", "published-elsewhere"),
+					resource.TestCheckResourceAttr(componentDataSourceName, "latest_version_number", "3"),
+				),
+			},
+			{
+				Config:      strings.Replace(componentLookupConfig, "public = false", "public = false\n    version_number = 9", 1),
+				ExpectError: regexp.MustCompile(`has no version 9; its latest is 3`),
+			},
+			{
+				Config:      strings.Replace(componentLookupConfig, "key = prismatic_component.component.key", `key = "missing"`, 1),
+				ExpectError: regexp.MustCompile(`No private component has the key "missing"`),
+			},
+		},
+	})
+}
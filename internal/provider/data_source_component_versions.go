@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+)
+
+var (
+	_ datasource.DataSource              = (*componentVersionsDataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*componentVersionsDataSource)(nil)
+)
+
+type componentVersionsDataSource struct {
+	client *graphql.Client
+}
+
+type componentVersionsModel struct {
+	Id       types.String            `tfsdk:"id"`
+	Key      types.String            `tfsdk:"key"`
+	Versions []componentVersionModel `tfsdk:"versions"`
+}
+
+type componentVersionModel struct {
+	Id               types.String `tfsdk:"id"`
+	VersionNumber    types.Int64  `tfsdk:"version_number"`
+	VersionCreatedAt types.String `tfsdk:"version_created_at"`
+	Signature        types.String `tfsdk:"signature"`
+	Public           types.Bool   `tfsdk:"public"`
+	CustomerId       types.String `tfsdk:"customer_id"`
+}
+
+func (d *componentVersionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_component_versions"
+}
+
+func (d *componentVersionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data source to list the published versions of a Prismatic Component.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Identifier for this data source: the Component key.",
+			},
+			"key": schema.StringAttribute{
+				Required:    true,
+				Description: "The key of the Component.",
+			},
+			"versions": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Every published version of Components with the key, newest first. A public Component and a private one may share a key; public tells them apart.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The ID of the Component version",
+						},
+						"version_number": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The version number",
+						},
+						"version_created_at": schema.StringAttribute{
+							Computed:    true,
+							Description: "When the version was published",
+						},
+						"signature": schema.StringAttribute{
+							Computed:    true,
+							Description: "The checksum of the bundle the version was published from",
+						},
+						"public": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the Component is public, available to all Prismatic organizations",
+						},
+						"customer_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The ID of the Customer the Component belongs to, or null for a Component of the Organization",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *componentVersionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *componentVersionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config componentVersionsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := componentVersionsModel{
+		Id:       config.Key,
+		Key:      config.Key,
+		Versions: []componentVersionModel{},
+	}
+	variables := map[string]interface{}{
+		"key":   graphql.String(config.Key.ValueString()),
+		"after": (*graphql.String)(nil),
+	}
+	for {
+		// allVersions lists each published version, which the API keeps as a Component
+		// record of its own, rather than only the latest.
+		var query struct {
+			Components struct {
+				Nodes []struct {
+					Id               graphql.ID
+					VersionNumber    graphql.Int
+					VersionCreatedAt graphql.String
+					Signature        graphql.String
+					Public           graphql.Boolean
+					Customer         struct {
+						Id graphql.ID
+					}
+				}
+				PageInfo pageInfo
+			} `graphql:"components(key: $key, allVersions: true, after: $after)"`
+		}
+		if err := d.client.Query(ctx, &query, variables); err != nil {
+			resp.Diagnostics.AddError("Unable to read component versions", err.Error())
+			return
+		}
+
+		for _, node := range query.Components.Nodes {
+			customerId := types.StringNull()
+			if id, ok := node.Customer.Id.(string); ok {
+				customerId = types.StringValue(id)
+			}
+			state.Versions = append(state.Versions, componentVersionModel{
+				Id:               types.StringValue(node.Id.(string)),
+				VersionNumber:    types.Int64Value(int64(node.VersionNumber)),
+				VersionCreatedAt: types.StringValue(string(node.VersionCreatedAt)),
+				Signature:        types.StringValue(string(node.Signature)),
+				Public:           types.BoolValue(bool(node.Public)),
+				CustomerId:       customerId,
+			})
+		}
+		if !query.Components.PageInfo.HasNextPage || query.Components.PageInfo.EndCursor == nil {
+			break
+		}
+		variables["after"] = query.Components.PageInfo.EndCursor
+	}
+	sort.SliceStable(state.Versions, func(i, j int) bool {
+		return state.Versions[i].VersionNumber.ValueInt64() > state.Versions[j].VersionNumber.ValueInt64()
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
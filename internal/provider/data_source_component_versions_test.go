@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const componentVersionsDataSourceName = "data.prismatic_component_versions.versions"
+
+func TestAccDataSourceComponentVersions_basic(t *testing.T) {
+	config := `
+data "prismatic_component_bundle" "bundle" {
+    bundle_directory = "../../test/data/component/code"
+    bundle_path = "../../test/data/component/bundle.zip"
+}
+
+resource "prismatic_component" "component" {
+    bundle_directory = data.prismatic_component_bundle.bundle.bundle_directory
+    bundle_path = data.prismatic_component_bundle.bundle.bundle_path
+    signature = data.prismatic_component_bundle.bundle.signature
+    manifest_path = "component.json"
+}
+
+data "prismatic_component_versions" "versions" {
+    key = prismatic_component.component.key
+    depends_on = [prismatic_component.component]
+}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(componentVersionsDataSourceName, "id", "componentKey"),
+					resource.TestCheckResourceAttrSet(componentVersionsDataSourceName, "versions.#"),
+					resource.TestCheckResourceAttrPair(componentVersionsDataSourceName, "versions.0.version_number", "prismatic_component.component", "version_number"),
+					resource.TestCheckResourceAttrPair(componentVersionsDataSourceName, "versions.0.version_created_at", "prismatic_component.component", "version_created_at"),
+					resource.TestCheckResourceAttrPair(componentVersionsDataSourceName, "versions.0.signature", "prismatic_component.component", "signature"),
+					resource.TestCheckResourceAttr(componentVersionsDataSourceName, "versions.0.public", "false"),
+				),
+			},
+		},
+	})
+}
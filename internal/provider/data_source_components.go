@@ -19,8 +19,12 @@ type componentsDataSource struct {
 }
 
 type componentsModel struct {
-	Id         types.String     `tfsdk:"id"`
-	Components []componentModel `tfsdk:"components"`
+	Id            types.String     `tfsdk:"id"`
+	KeyContains   types.String     `tfsdk:"key_contains"`
+	LabelContains types.String     `tfsdk:"label_contains"`
+	Category      types.String     `tfsdk:"category"`
+	Public        types.Bool       `tfsdk:"public"`
+	Components    []componentModel `tfsdk:"components"`
 }
 
 type componentModel struct {
@@ -42,6 +46,22 @@ func (d *componentsDataSource) Schema(ctx context.Context, req datasource.Schema
 				Computed:    true,
 				Description: "Identifier for this data source.",
 			},
+			"key_contains": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list Components whose key contains this text, ignoring case.",
+			},
+			"label_contains": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list Components whose label contains this text, ignoring case.",
+			},
+			"category": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list Components in this category.",
+			},
+			"public": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only list public Components when true, or the Organization's private Components when false.",
+			},
 			"components": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -74,34 +94,52 @@ func (d *componentsDataSource) Configure(ctx context.Context, req datasource.Con
 }
 
 func (d *componentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var query struct {
-		Components struct {
-			Nodes []struct {
-				Id          string
-				Key         string
-				Label       string
-				Description string
-			}
-		}
-	}
-
-	if err := d.client.Query(ctx, &query, nil); err != nil {
-		resp.Diagnostics.AddError("Unable to read components", err.Error())
+	var config componentsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	state := componentsModel{
-		Id:         types.StringValue("components"),
-		Components: make([]componentModel, 0, len(query.Components.Nodes)),
+	variables := map[string]interface{}{
+		"after":         (*graphql.String)(nil),
+		"keyContains":   stringFilter(config.KeyContains),
+		"labelContains": stringFilter(config.LabelContains),
+		"category":      stringFilter(config.Category),
+		"public":        boolFilter(config.Public),
 	}
 
-	for _, componentNode := range query.Components.Nodes {
-		state.Components = append(state.Components, componentModel{
-			ComponentId:          types.StringValue(componentNode.Id),
-			ComponentKey:         types.StringValue(componentNode.Key),
-			ComponentLabel:       types.StringValue(componentNode.Label),
-			ComponentDescription: types.StringValue(componentNode.Description),
-		})
+	state := config
+	state.Id = types.StringValue("components")
+	state.Components = []componentModel{}
+	for {
+		var query struct {
+			Components struct {
+				Nodes []struct {
+					Id          string
+					Key         string
+					Label       string
+					Description string
+				}
+				PageInfo pageInfo
+			} `graphql:"components(after: $after, key_Icontains: $keyContains, label_Icontains: $labelContains, category: $category, public: $public)"`
+		}
+		if err := d.client.Query(ctx, &query, variables); err != nil {
+			resp.Diagnostics.AddError("Unable to read components", err.Error())
+			return
+		}
+
+		for _, componentNode := range query.Components.Nodes {
+			state.Components = append(state.Components, componentModel{
+				ComponentId:          types.StringValue(componentNode.Id),
+				ComponentKey:         types.StringValue(componentNode.Key),
+				ComponentLabel:       types.StringValue(componentNode.Label),
+				ComponentDescription: types.StringValue(componentNode.Description),
+			})
+		}
+		if !query.Components.PageInfo.HasNextPage || query.Components.PageInfo.EndCursor == nil {
+			break
+		}
+		variables["after"] = query.Components.PageInfo.EndCursor
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
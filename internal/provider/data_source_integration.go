@@ -0,0 +1,352 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+)
+
+var (
+	_ datasource.DataSource                   = (*integrationDataSource)(nil)
+	_ datasource.DataSourceWithConfigure      = (*integrationDataSource)(nil)
+	_ datasource.DataSourceWithValidateConfig = (*integrationDataSource)(nil)
+)
+
+type integrationDataSource struct {
+	client *graphql.Client
+}
+
+type integrationDataSourceModel struct {
+	Id                  types.String              `tfsdk:"id"`
+	Name                types.String              `tfsdk:"name"`
+	IncludeDefinition   types.Bool                `tfsdk:"include_definition"`
+	Description         types.String              `tfsdk:"description"`
+	Category            types.String              `tfsdk:"category"`
+	Labels              types.Set                 `tfsdk:"labels"`
+	Published           types.Bool                `tfsdk:"published"`
+	LatestVersionNumber types.Int64               `tfsdk:"latest_version_number"`
+	Versions            []integrationVersionModel `tfsdk:"versions"`
+	Flows               []integrationFlowModel    `tfsdk:"flows"`
+	Definition          types.String              `tfsdk:"definition"`
+}
+
+type integrationVersionModel struct {
+	Id            types.String `tfsdk:"id"`
+	VersionNumber types.Int64  `tfsdk:"version_number"`
+	Comment       types.String `tfsdk:"comment"`
+	CreatedAt     types.String `tfsdk:"created_at"`
+	Available     types.Bool   `tfsdk:"available"`
+}
+
+type integrationFlowModel struct {
+	Id            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Description   types.String `tfsdk:"description"`
+	IsSynchronous types.Bool   `tfsdk:"is_synchronous"`
+	WebhookUrl    types.String `tfsdk:"webhook_url"`
+}
+
+func (d *integrationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_integration"
+}
+
+func (d *integrationDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data source to look up a Prismatic Integration by ID or name.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of the Integration. Exactly one of id and name must be set.",
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The exact name of the Integration. Exactly one of id and name must be set.",
+			},
+			"include_definition": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to fetch the YAML definition of the Integration into definition. Defaults to false.",
+			},
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "The description of the Integration",
+			},
+			"category": schema.StringAttribute{
+				Computed:    true,
+				Description: "The category of the Integration",
+			},
+			"labels": schema.SetAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The labels of the Integration",
+			},
+			"published": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether any version of the Integration has been published",
+			},
+			"latest_version_number": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The latest published version number of the Integration, or null if it has not been published",
+			},
+			"versions": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The published versions of the Integration, newest first.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The ID of the Integration version",
+						},
+						"version_number": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The version number",
+						},
+						"comment": schema.StringAttribute{
+							Computed:    true,
+							Description: "The comment the version was published with",
+						},
+						"created_at": schema.StringAttribute{
+							Computed:    true,
+							Description: "When the version was published",
+						},
+						"available": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the version is available to deploy",
+						},
+					},
+				},
+			},
+			"flows": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The flows of the Integration.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The ID of the flow",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The name of the flow",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "The description of the flow",
+						},
+						"is_synchronous": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the flow's trigger waits for the flow to finish before responding",
+						},
+						"webhook_url": schema.StringAttribute{
+							Computed:    true,
+							Description: "The webhook URL that triggers the flow in the Integration's test instance",
+						},
+					},
+				},
+			},
+			"definition": schema.StringAttribute{
+				Computed:    true,
+				Description: "The YAML definition of the Integration, or null unless include_definition is true",
+			},
+		},
+	}
+}
+
+func (d *integrationDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *integrationDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var id, name types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name"), &name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if id.IsNull() == name.IsNull() {
+		resp.Diagnostics.AddError(
+			"Invalid integration lookup",
+			"Exactly one of id and name must be set.",
+		)
+	}
+}
+
+func (d *integrationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config integrationDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := config.Id.ValueString()
+	if config.Id.IsNull() {
+		id = d.findByName(ctx, config.Name.ValueString(), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var query struct {
+		Integration struct {
+			Id              graphql.ID
+			Name            graphql.String
+			Description     graphql.String
+			Category        graphql.String
+			Labels          []graphql.String
+			VersionSequence struct {
+				Nodes []struct {
+					Id                 graphql.ID
+					VersionNumber      graphql.Int
+					VersionComment     graphql.String
+					VersionCreatedAt   graphql.String
+					VersionIsAvailable graphql.Boolean
+				}
+			}
+			Flows struct {
+				Nodes []struct {
+					Id            graphql.ID
+					Name          graphql.String
+					Description   graphql.String
+					IsSynchronous graphql.Boolean
+					TestUrl       graphql.String
+				}
+			}
+		} `graphql:"integration(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id": graphql.ID(id),
+	}
+	if err := d.client.Query(ctx, &query, variables); err != nil {
+		if isRecordNotFound(err) {
+			resp.Diagnostics.AddAttributeError(path.Root("id"), "Integration not found", fmt.Sprintf("No integration has the ID %q.", id))
+			return
+		}
+		resp.Diagnostics.AddError("Unable to read integration", err.Error())
+		return
+	}
+
+	integration := query.Integration
+	labels := make([]attr.Value, 0, len(integration.Labels))
+	for _, label := range integration.Labels {
+		labels = append(labels, types.StringValue(string(label)))
+	}
+	labelSet, diags := types.SetValue(types.StringType, labels)
+	resp.Diagnostics.Append(diags...)
+
+	state := integrationDataSourceModel{
+		Id:                  types.StringValue(integration.Id.(string)),
+		Name:                types.StringValue(string(integration.Name)),
+		IncludeDefinition:   config.IncludeDefinition,
+		Description:         types.StringValue(string(integration.Description)),
+		Category:            types.StringValue(string(integration.Category)),
+		Labels:              labelSet,
+		Published:           types.BoolValue(false),
+		LatestVersionNumber: types.Int64Null(),
+		Versions:            make([]integrationVersionModel, 0, len(integration.VersionSequence.Nodes)),
+		Flows:               make([]integrationFlowModel, 0, len(integration.Flows.Nodes)),
+		Definition:          types.StringNull(),
+	}
+	// The version sequence includes the draft, numbered 0, which is not a
+	// published version.
+	for _, version := range integration.VersionSequence.Nodes {
+		if version.VersionNumber == 0 {
+			continue
+		}
+		state.Versions = append(state.Versions, integrationVersionModel{
+			Id:            types.StringValue(version.Id.(string)),
+			VersionNumber: types.Int64Value(int64(version.VersionNumber)),
+			Comment:       types.StringValue(string(version.VersionComment)),
+			CreatedAt:     types.StringValue(string(version.VersionCreatedAt)),
+			Available:     types.BoolValue(bool(version.VersionIsAvailable)),
+		})
+	}
+	sort.SliceStable(state.Versions, func(i, j int) bool {
+		return state.Versions[i].VersionNumber.ValueInt64() > state.Versions[j].VersionNumber.ValueInt64()
+	})
+	if len(state.Versions) > 0 {
+		state.Published = types.BoolValue(true)
+		state.LatestVersionNumber = state.Versions[0].VersionNumber
+	}
+	for _, flow := range integration.Flows.Nodes {
+		state.Flows = append(state.Flows, integrationFlowModel{
+			Id:            types.StringValue(flow.Id.(string)),
+			Name:          types.StringValue(string(flow.Name)),
+			Description:   types.StringValue(string(flow.Description)),
+			IsSynchronous: types.BoolValue(bool(flow.IsSynchronous)),
+			WebhookUrl:    types.StringValue(string(flow.TestUrl)),
+		})
+	}
+
+	// Definitions can be large, so they are only fetched when asked for.
+	if config.IncludeDefinition.ValueBool() {
+		var definitionQuery struct {
+			Integration struct {
+				Definition graphql.String
+			} `graphql:"integration(id: $id)"`
+		}
+		if err := d.client.Query(ctx, &definitionQuery, variables); err != nil {
+			resp.Diagnostics.AddError("Unable to read integration definition", err.Error())
+			return
+		}
+		state.Definition = types.StringValue(string(definitionQuery.Integration.Definition))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// findByName returns the id of the Integration with the given name, recording
+// diagnostics when none or more than one Integration has it.
+func (d *integrationDataSource) findByName(ctx context.Context, name string, diags *diag.Diagnostics) string {
+	variables := map[string]interface{}{
+		"after": (*graphql.String)(nil),
+		"name":  graphql.String(name),
+	}
+
+	var ids []string
+	for {
+		var query struct {
+			Integrations struct {
+				Nodes []struct {
+					Id graphql.ID
+				}
+				PageInfo pageInfo
+			} `graphql:"integrations(after: $after, name: $name)"`
+		}
+		if err := d.client.Query(ctx, &query, variables); err != nil {
+			diags.AddError("Unable to read integrations", err.Error())
+			return ""
+		}
+
+		for _, node := range query.Integrations.Nodes {
+			ids = append(ids, node.Id.(string))
+		}
+		if !query.Integrations.PageInfo.HasNextPage || query.Integrations.PageInfo.EndCursor == nil {
+			break
+		}
+		variables["after"] = query.Integrations.PageInfo.EndCursor
+	}
+
+	switch len(ids) {
+	case 0:
+		diags.AddAttributeError(path.Root("name"), "Integration not found", fmt.Sprintf("No integration has the name %q.", name))
+	case 1:
+		return ids[0]
+	default:
+		diags.AddAttributeError(
+			path.Root("name"),
+			"Ambiguous integration name",
+			fmt.Sprintf("%d integrations have the name %q. Set id to choose one.", len(ids), name),
+		)
+	}
+	return ""
+}
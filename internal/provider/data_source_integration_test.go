@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const integrationDataSourceName = "data.prismatic_integration.integration"
+
+// integrationLookupConfig publishes an integration and looks it up by the
+// given argument, such as `id = prismatic_integration.integration.id`.
+func integrationLookupConfig(lookup string) string {
+	return integrationVersionConfig(baseDefinition, "Initial version") + fmt.Sprintf(`
+
+data "prismatic_integration" "integration" {
+  %s
+  include_definition = true
+
+  depends_on = [prismatic_integration_version.version]
+}`, lookup)
+}
+
+func TestAccDataSourceIntegration_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckIntegrationResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: integrationLookupConfig("id = prismatic_integration.integration.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(integrationDataSourceName, "name", expectedName),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "description", expectedDescription),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "published", "true"),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "latest_version_number", "1"),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "versions.0.comment", "Initial version"),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "flows.0.name", "Flow 1"),
+					resource.TestCheckResourceAttrSet(integrationDataSourceName, "flows.0.webhook_url"),
+					resource.TestCheckResourceAttrSet(integrationDataSourceName, "definition"),
+				),
+			},
+			{
+				Config: integrationLookupConfig(fmt.Sprintf("name = %q", expectedName)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(integrationDataSourceName, "id", resourceName, "id"),
+				),
+			},
+		},
+	})
+}
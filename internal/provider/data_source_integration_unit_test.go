@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestUnitDataSourceIntegration_lookup(t *testing.T) {
+	server := testUnitPreCheck(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckIntegrationResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: integrationLookupConfig("id = prismatic_integration.integration.id"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(integrationDataSourceName, "name", expectedName),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "published", "true"),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "latest_version_number", "1"),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "versions.#", "1"),
+					resource.TestCheckResourceAttrPair(integrationDataSourceName, "versions.0.id", integrationVersionResourceName, "id"),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "versions.0.available", "true"),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "flows.#", "1"),
+					resource.TestCheckResourceAttr(integrationDataSourceName, "flows.0.is_synchronous", "false"),
+					resource.TestMatchResourceAttr(integrationDataSourceName, "flows.0.webhook_url", regexp.MustCompile("^"+regexp.QuoteMeta(server.URL)+"/trigger/")),
+					resource.TestCheckResourceAttrPair(integrationDataSourceName, "definition", resourceName, "definition"),
+				),
+			},
+			{
+				Config: strings.Replace(integrationLookupConfig(fmt.Sprintf("name = %q", expectedName)), "include_definition = true", "", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(integrationDataSourceName, "id", resourceName, "id"),
+					resource.TestCheckNoResourceAttr(integrationDataSourceName, "definition"),
+				),
+			},
+			{
+				Config:      integrationLookupConfig(`name = "missing"`),
+				ExpectError: regexp.MustCompile(`No integration has the name "missing"`),
+			},
+			{
+				Config:      integrationLookupConfig("id = prismatic_integration.integration.id\n  name = \"missing\""),
+				ExpectError: regexp.MustCompile(`Exactly one of id and name must be set`),
+			},
+		},
+	})
+}
@@ -20,6 +20,9 @@ type integrationsDataSource struct {
 
 type integrationsModel struct {
 	Id           types.String       `tfsdk:"id"`
+	NameContains types.String       `tfsdk:"name_contains"`
+	Category     types.String       `tfsdk:"category"`
+	Labels       types.Set          `tfsdk:"labels"`
 	Integrations []integrationModel `tfsdk:"integrations"`
 }
 
@@ -40,6 +43,19 @@ func (d *integrationsDataSource) Schema(ctx context.Context, req datasource.Sche
 				Computed:    true,
 				Description: "Identifier for this data source.",
 			},
+			"name_contains": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list Integrations whose name contains this text, ignoring case.",
+			},
+			"category": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list Integrations in this category.",
+			},
+			"labels": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Only list Integrations that have all of these labels.",
+			},
 			"integrations": schema.ListNestedAttribute{
 				Computed:    true,
 				Description: "Data source to list Prismatic Integrations",
@@ -69,31 +85,54 @@ func (d *integrationsDataSource) Configure(ctx context.Context, req datasource.C
 }
 
 func (d *integrationsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var query struct {
-		Integrations struct {
-			Nodes []struct {
-				Id         string
-				Name       string
-				Definition string
-			}
-		}
-	}
-
-	if err := d.client.Query(ctx, &query, nil); err != nil {
-		resp.Diagnostics.AddError("Unable to read integrations", err.Error())
+	var config integrationsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	state := integrationsModel{
-		Id:           types.StringValue("integrations"),
-		Integrations: make([]integrationModel, 0, len(query.Integrations.Nodes)),
+	var labels *[]graphql.String
+	if !config.Labels.IsNull() && !config.Labels.IsUnknown() {
+		values := labelsFromSet(ctx, config.Labels, &resp.Diagnostics)
+		labels = &values
+	}
+	variables := map[string]interface{}{
+		"after":        (*graphql.String)(nil),
+		"nameContains": stringFilter(config.NameContains),
+		"category":     stringFilter(config.Category),
+		"labels":       labels,
 	}
-	for _, integrationNode := range query.Integrations.Nodes {
-		state.Integrations = append(state.Integrations, integrationModel{
-			IntegrationId:         types.StringValue(integrationNode.Id),
-			IntegrationName:       types.StringValue(integrationNode.Name),
-			IntegrationDefinition: types.StringValue(integrationNode.Definition),
-		})
+
+	state := config
+	state.Id = types.StringValue("integrations")
+	state.Integrations = []integrationModel{}
+	for {
+		var query struct {
+			Integrations struct {
+				Nodes []struct {
+					Id         string
+					Name       string
+					Definition string
+				}
+				PageInfo pageInfo
+			} `graphql:"integrations(after: $after, name_Icontains: $nameContains, category: $category, labels_Contains: $labels)"`
+		}
+		if err := d.client.Query(ctx, &query, variables); err != nil {
+			resp.Diagnostics.AddError("Unable to read integrations", err.Error())
+			return
+		}
+
+		for _, integrationNode := range query.Integrations.Nodes {
+			state.Integrations = append(state.Integrations, integrationModel{
+				IntegrationId:         types.StringValue(integrationNode.Id),
+				IntegrationName:       types.StringValue(integrationNode.Name),
+				IntegrationDefinition: types.StringValue(integrationNode.Definition),
+			})
+		}
+		if !query.Integrations.PageInfo.HasNextPage || query.Integrations.PageInfo.EndCursor == nil {
+			break
+		}
+		variables["after"] = query.Integrations.PageInfo.EndCursor
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
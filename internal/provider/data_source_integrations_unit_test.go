@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestUnitDataSourceIntegrations_pagination(t *testing.T) {
+	server := testUnitPreCheck(t)
+	// A page per integration, so listing them all walks every page.
+	server.SetPageSize(1)
+
+	config := resourceWithDefinition(baseDefinition) + fmt.Sprintf(`
+
+resource "prismatic_integration" "labeled" {
+  definition = <<EOF
+name: Labeled
+category: Acceptance
+labels: [team, reporting]
+%s
+EOF
+}
+
+data "prismatic_integrations" "all" {
+  depends_on = [prismatic_integration.integration, prismatic_integration.labeled]
+}
+
+data "prismatic_integrations" "named" {
+  name_contains = "acceptance"
+  depends_on    = [prismatic_integration.integration, prismatic_integration.labeled]
+}
+
+data "prismatic_integrations" "labeled" {
+  category   = "Acceptance"
+  labels     = ["reporting"]
+  depends_on = [prismatic_integration.integration, prismatic_integration.labeled]
+}`, `flows:
+  - name: Flow 1
+    steps:
+      - name: Integration Trigger
+        isTrigger: true
+        action:
+          component: {key: webhook-triggers, version: LATEST, isPublic: true}
+          key: webhook`)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckIntegrationResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.prismatic_integrations.all", "integrations.#", "2"),
+					resource.TestCheckResourceAttr("data.prismatic_integrations.named", "integrations.#", "1"),
+					resource.TestCheckResourceAttr("data.prismatic_integrations.named", "integrations.0.integration_name", expectedName),
+					resource.TestCheckResourceAttr("data.prismatic_integrations.labeled", "integrations.#", "1"),
+					resource.TestCheckResourceAttr("data.prismatic_integrations.labeled", "integrations.0.integration_name", "Labeled"),
+				),
+			},
+			// A name shared across pages is reported as ambiguous.
+			{
+				Config: config + fmt.Sprintf(`
+
+resource "prismatic_integration" "duplicate" {
+  definition = <<EOF
+%s
+EOF
+  depends_on = [prismatic_integration.integration]
+}
+
+data "prismatic_integration" "by_name" {
+  name       = %q
+  depends_on = [prismatic_integration.duplicate]
+}`, baseDefinition, expectedName),
+				ExpectError: regexp.MustCompile(`2 integrations have the name`),
+			},
+		},
+	})
+}
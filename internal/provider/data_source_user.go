@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+)
+
+var (
+	_ datasource.DataSource                   = (*userDataSource)(nil)
+	_ datasource.DataSourceWithConfigure      = (*userDataSource)(nil)
+	_ datasource.DataSourceWithValidateConfig = (*userDataSource)(nil)
+)
+
+type userDataSource struct {
+	client *graphql.Client
+}
+
+type userDataSourceModel struct {
+	Id         types.String `tfsdk:"id"`
+	Email      types.String `tfsdk:"email"`
+	ExternalId types.String `tfsdk:"external_id"`
+	CustomerId types.String `tfsdk:"customer_id"`
+	Name       types.String `tfsdk:"name"`
+	Role       types.String `tfsdk:"role"`
+	RoleName   types.String `tfsdk:"role_name"`
+	Phone      types.String `tfsdk:"phone"`
+	AvatarUrl  types.String `tfsdk:"avatar_url"`
+	CreatedAt  types.String `tfsdk:"created_at"`
+	UpdatedAt  types.String `tfsdk:"updated_at"`
+}
+
+func (d *userDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (d *userDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data source to look up an Organization or Customer User in Prismatic by email or external ID.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The unique identifier of the user.",
+			},
+			"email": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The email address of the user. Exactly one of email and external_id must be set.",
+			},
+			"external_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The external ID of the user. Exactly one of email and external_id must be set.",
+			},
+			"customer_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of the Customer the user belongs to, or null for an Organization user. When set, only that Customer's users are searched; otherwise Organization and Customer users both are.",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the user.",
+			},
+			"role": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the role assigned to the user.",
+			},
+			"role_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the role assigned to the user.",
+			},
+			"phone": schema.StringAttribute{
+				Computed:    true,
+				Description: "The phone number of the user.",
+			},
+			"avatar_url": schema.StringAttribute{
+				Computed:    true,
+				Description: "The URL of the user's avatar image.",
+			},
+			"created_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "The timestamp when the user was created.",
+			},
+			"updated_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "The timestamp when the user was last updated.",
+			},
+		},
+	}
+}
+
+func (d *userDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *userDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var email, externalId types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("email"), &email)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("external_id"), &externalId)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if email.IsNull() == externalId.IsNull() {
+		resp.Diagnostics.AddError(
+			"Invalid user lookup",
+			"Exactly one of email and external_id must be set.",
+		)
+	}
+}
+
+func (d *userDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config userDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var customer *graphql.ID
+	if !config.CustomerId.IsNull() {
+		id := graphql.ID(config.CustomerId.ValueString())
+		customer = &id
+	}
+	var query struct {
+		Users struct {
+			Nodes []struct {
+				Id         graphql.ID
+				Email      graphql.String
+				Name       graphql.String
+				Phone      graphql.String
+				ExternalId graphql.String
+				AvatarUrl  graphql.String
+				CreatedAt  graphql.String
+				UpdatedAt  graphql.String
+				Role       struct {
+					Id   graphql.ID
+					Name graphql.String
+				}
+				Customer struct {
+					Id graphql.ID
+				}
+			}
+		} `graphql:"users(email: $email, externalId: $externalId, customer: $customer)"`
+	}
+	variables := map[string]interface{}{
+		"email":      stringFilter(config.Email),
+		"externalId": stringFilter(config.ExternalId),
+		"customer":   customer,
+	}
+	if err := d.client.Query(ctx, &query, variables); err != nil {
+		resp.Diagnostics.AddError("Unable to read users", err.Error())
+		return
+	}
+
+	attribute, match := "email", config.Email.ValueString()
+	if config.Email.IsNull() {
+		attribute, match = "external_id", config.ExternalId.ValueString()
+	}
+	within := ""
+	if !config.CustomerId.IsNull() {
+		within = fmt.Sprintf(" in the customer %q", config.CustomerId.ValueString())
+	}
+	switch nodes := query.Users.Nodes; len(nodes) {
+	case 0:
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attribute),
+			"User not found",
+			fmt.Sprintf("No user%s has the %s %q.", within, attribute, match),
+		)
+		return
+	case 1:
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attribute),
+			"Ambiguous user lookup",
+			fmt.Sprintf("%d users%s have the %s %q. Set customer_id to choose one.", len(nodes), within, attribute, match),
+		)
+		return
+	}
+
+	user := query.Users.Nodes[0]
+	state := userDataSourceModel{
+		Id:         types.StringValue(user.Id.(string)),
+		Email:      types.StringValue(string(user.Email)),
+		ExternalId: types.StringValue(string(user.ExternalId)),
+		CustomerId: types.StringNull(),
+		Name:       types.StringValue(string(user.Name)),
+		Role:       types.StringValue(user.Role.Id.(string)),
+		RoleName:   types.StringValue(string(user.Role.Name)),
+		Phone:      types.StringValue(string(user.Phone)),
+		AvatarUrl:  types.StringValue(string(user.AvatarUrl)),
+		CreatedAt:  types.StringValue(string(user.CreatedAt)),
+		UpdatedAt:  types.StringValue(string(user.UpdatedAt)),
+	}
+	if customerId, ok := user.Customer.Id.(string); ok {
+		state.CustomerId = types.StringValue(customerId)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const userDataSourceName = "data.prismatic_user.test"
+
+// userLookupConfig creates an organization user with an external ID and looks it
+// up by the given argument, such as `email = prismatic_organization_user.test.email`.
+func userLookupConfig(lookup string) string {
+	return organizationUserConfig(testUserEmail, testUserName, "local.admin_role.id", "", "terraform-test-user") + fmt.Sprintf(`
+data "prismatic_user" "test" {
+  %s
+
+  depends_on = [prismatic_organization_user.test]
+}
+`, lookup)
+}
+
+func TestAccDataSourceUser_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckOrganizationUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: userLookupConfig(fmt.Sprintf("email = %q", testUserEmail)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(userDataSourceName, "id", organizationUserResourceName, "id"),
+					resource.TestCheckResourceAttr(userDataSourceName, "name", testUserName),
+					resource.TestCheckResourceAttrPair(userDataSourceName, "role", organizationUserResourceName, "role"),
+					resource.TestCheckResourceAttr(userDataSourceName, "role_name", "Admin"),
+					resource.TestCheckNoResourceAttr(userDataSourceName, "customer_id"),
+				),
+			},
+			{
+				Config: userLookupConfig(`external_id = "terraform-test-user"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(userDataSourceName, "id", organizationUserResourceName, "id"),
+					resource.TestCheckResourceAttr(userDataSourceName, "email", testUserEmail),
+				),
+			},
+		},
+	})
+}
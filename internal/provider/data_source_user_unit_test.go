@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/prismatic-io/terraform-provider-prismatic/internal/fakeprismatic"
+)
+
+func TestUnitDataSourceUser_lookup(t *testing.T) {
+	server := testUnitPreCheck(t)
+
+	base := organizationUserConfig(testUserEmail, testUserName, "local.admin_role.id", "", "terraform-test-user") + customerConfig(testCustomerName)
+	lookup := func(arguments string) string {
+		return base + fmt.Sprintf(`
+data "prismatic_user" "test" {
+  %s
+
+  depends_on = [prismatic_organization_user.test, prismatic_customer.test]
+}
+`, arguments)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckOrganizationUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: base,
+				Check:  testUnitAddCustomerUser(server, customerResourceName, testUserEmail, "terraform-test-user"),
+			},
+			// The organization user and the customer user share the email.
+			{
+				Config:      lookup(fmt.Sprintf("email = %q", testUserEmail)),
+				ExpectError: regexp.MustCompile(`2 users have the email`),
+			},
+			{
+				Config: lookup(fmt.Sprintf("email = %q\n  customer_id = prismatic_customer.test.id", testUserEmail)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(userDataSourceName, "customer_id", customerResourceName, "id"),
+					resource.TestCheckResourceAttr(userDataSourceName, "external_id", "terraform-test-user"),
+					resource.TestCheckResourceAttr(userDataSourceName, "role_name", "Member"),
+				),
+			},
+			{
+				Config: lookup(`external_id = "terraform-test-user"` + "\n  customer_id = prismatic_customer.test.id"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(userDataSourceName, "email", testUserEmail),
+					resource.TestCheckResourceAttrPair(userDataSourceName, "customer_id", customerResourceName, "id"),
+				),
+			},
+			{
+				Config:      lookup(`email = "missing@example.com"`),
+				ExpectError: regexp.MustCompile(`No user has the email "missing@example.com"`),
+			},
+			{
+				Config:      lookup(`email = "missing@example.com"` + "\n  external_id = \"terraform-test-user\""),
+				ExpectError: regexp.MustCompile(`Exactly one of email and external_id must be set`),
+			},
+		},
+	})
+}
+
+// testUnitAddCustomerUser adds a user to the customer resource name refers to,
+// which the provider has no resource to manage.
+func testUnitAddCustomerUser(server *fakeprismatic.Server, name, email, externalId string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found", name)
+		}
+		_, err := server.AddCustomerUser(rs.Primary.ID, email, externalId)
+		return err
+	}
+}
@@ -21,6 +21,7 @@ type usersDataSource struct {
 type usersModel struct {
 	Id             types.String `tfsdk:"id"`
 	CustomerIsNull types.Bool   `tfsdk:"customer_is_null"`
+	EmailContains  types.String `tfsdk:"email_contains"`
 	Users          []userModel  `tfsdk:"users"`
 }
 
@@ -55,6 +56,10 @@ func (d *usersDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 				Computed:    true,
 				Description: "Filter for users where customer is NULL. When true (default), returns only Organization users. When false, returns Customer users.",
 			},
+			"email_contains": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list users whose email address contains this text, ignoring case.",
+			},
 			"users": schema.ListNestedAttribute{
 				Computed:    true,
 				Description: "List of users in Prismatic.",
@@ -123,53 +128,62 @@ func (d *usersDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		customerIsNull = config.CustomerIsNull.ValueBool()
 	}
 
-	// users returns UserConnection! (with nodes)
-	var query struct {
-		Users struct {
-			Nodes []struct {
-				Id         graphql.ID
-				Email      graphql.String
-				Name       graphql.String
-				Phone      graphql.String
-				ExternalId graphql.String
-				AvatarUrl  graphql.String
-				CreatedAt  graphql.String
-				UpdatedAt  graphql.String
-				Role       struct {
-					Id   graphql.ID
-					Name graphql.String
-				}
-			}
-		} `graphql:"users(customer_Isnull: $customerIsNull)"`
-	}
-
 	variables := map[string]interface{}{
+		"after":          (*graphql.String)(nil),
 		"customerIsNull": graphql.Boolean(customerIsNull),
-	}
-
-	if err := d.client.Query(ctx, &query, variables); err != nil {
-		resp.Diagnostics.AddError("Unable to read users", err.Error())
-		return
+		"emailContains":  stringFilter(config.EmailContains),
 	}
 
 	state := usersModel{
 		Id:             types.StringValue("users"),
 		CustomerIsNull: types.BoolValue(customerIsNull),
-		Users:          make([]userModel, 0, len(query.Users.Nodes)),
+		EmailContains:  config.EmailContains,
+		Users:          []userModel{},
 	}
-	for _, userNode := range query.Users.Nodes {
-		state.Users = append(state.Users, userModel{
-			Id:         types.StringValue(userNode.Id.(string)),
-			Email:      types.StringValue(string(userNode.Email)),
-			Name:       types.StringValue(string(userNode.Name)),
-			RoleId:     types.StringValue(userNode.Role.Id.(string)),
-			RoleName:   types.StringValue(string(userNode.Role.Name)),
-			Phone:      types.StringValue(string(userNode.Phone)),
-			ExternalId: types.StringValue(string(userNode.ExternalId)),
-			AvatarUrl:  types.StringValue(string(userNode.AvatarUrl)),
-			CreatedAt:  types.StringValue(string(userNode.CreatedAt)),
-			UpdatedAt:  types.StringValue(string(userNode.UpdatedAt)),
-		})
+	for {
+		// users returns UserConnection! (with nodes)
+		var query struct {
+			Users struct {
+				Nodes []struct {
+					Id         graphql.ID
+					Email      graphql.String
+					Name       graphql.String
+					Phone      graphql.String
+					ExternalId graphql.String
+					AvatarUrl  graphql.String
+					CreatedAt  graphql.String
+					UpdatedAt  graphql.String
+					Role       struct {
+						Id   graphql.ID
+						Name graphql.String
+					}
+				}
+				PageInfo pageInfo
+			} `graphql:"users(after: $after, customer_Isnull: $customerIsNull, email_Icontains: $emailContains)"`
+		}
+		if err := d.client.Query(ctx, &query, variables); err != nil {
+			resp.Diagnostics.AddError("Unable to read users", err.Error())
+			return
+		}
+
+		for _, userNode := range query.Users.Nodes {
+			state.Users = append(state.Users, userModel{
+				Id:         types.StringValue(userNode.Id.(string)),
+				Email:      types.StringValue(string(userNode.Email)),
+				Name:       types.StringValue(string(userNode.Name)),
+				RoleId:     types.StringValue(userNode.Role.Id.(string)),
+				RoleName:   types.StringValue(string(userNode.Role.Name)),
+				Phone:      types.StringValue(string(userNode.Phone)),
+				ExternalId: types.StringValue(string(userNode.ExternalId)),
+				AvatarUrl:  types.StringValue(string(userNode.AvatarUrl)),
+				CreatedAt:  types.StringValue(string(userNode.CreatedAt)),
+				UpdatedAt:  types.StringValue(string(userNode.UpdatedAt)),
+			})
+		}
+		if !query.Users.PageInfo.HasNextPage || query.Users.PageInfo.EndCursor == nil {
+			break
+		}
+		variables["after"] = query.Users.PageInfo.EndCursor
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -17,6 +17,12 @@ data "prismatic_users" "test" {
 	usersConfigDefault = `
 data "prismatic_users" "test" {
 }
+`
+	// usersConfigNoMatch filters on an email address no user has.
+	usersConfigNoMatch = `
+data "prismatic_users" "test" {
+  email_contains = "no-such-user.invalid"
+}
 `
 )
 
@@ -39,6 +45,12 @@ func TestAccDataSourceUsers_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(usersDataSourceName, "customer_is_null", "true"),
 				),
 			},
+			{
+				Config: usersConfigNoMatch,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(usersDataSourceName, "users.#", "0"),
+				),
+			},
 		},
 	})
 }
@@ -1,30 +1,60 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/prismatic-io/terraform-provider-prismatic/internal/util"
 	"github.com/shurcooL/graphql"
 )
 
+// prismaticClients is the data the provider passes to resources and data sources:
+// the GraphQL client and the plain HTTP client used for uploads, which share the
+// provider's retry behavior.
+type prismaticClients struct {
+	graphql *graphql.Client
+	http    *http.Client
+}
+
 // clientFromProviderData extracts the configured GraphQL client passed to a resource
 // or data source during configuration. It returns nil before the provider has been
 // configured (ProviderData is nil) and records a diagnostic if the data is an
 // unexpected type.
 func clientFromProviderData(providerData interface{}, diags *diag.Diagnostics) *graphql.Client {
+	if clients := clientsFromProviderData(providerData, diags); clients != nil {
+		return clients.graphql
+	}
+	return nil
+}
+
+// httpClientFromProviderData is the clientFromProviderData counterpart for the
+// upload HTTP client.
+func httpClientFromProviderData(providerData interface{}, diags *diag.Diagnostics) *http.Client {
+	if clients := clientsFromProviderData(providerData, diags); clients != nil {
+		return clients.http
+	}
+	return nil
+}
+
+func clientsFromProviderData(providerData interface{}, diags *diag.Diagnostics) *prismaticClients {
 	if providerData == nil {
 		return nil
 	}
-	client, ok := providerData.(*graphql.Client)
+	clients, ok := providerData.(*prismaticClients)
 	if !ok {
 		diags.AddError(
 			"Unexpected Provider Data Type",
-			"Expected *graphql.Client. This is a bug in the provider; please report it.",
+			"Expected *prismaticClients. This is a bug in the provider; please report it.",
 		)
 		return nil
 	}
-	return client
+	return clients
 }
 
 // gqlErrorDiagnostics converts the user-facing field errors returned by a Prismatic
@@ -46,3 +76,92 @@ func gqlErrorDiagnostics(errs util.GqlErrors) diag.Diagnostics {
 func isRecordNotFound(err error) bool {
 	return err != nil && strings.Contains(err.Error(), "Record not found")
 }
+
+// pageInfo is the selection of a connection's pagination details. List data
+// sources pass endCursor back as the after argument until hasNextPage is false,
+// since the API returns a single page per query.
+type pageInfo struct {
+	HasNextPage graphql.Boolean
+	EndCursor   *graphql.String
+}
+
+// stringFilter returns the variable for an optional string filter argument, which
+// is null, and so ignored by the API, when v is not set.
+func stringFilter(v types.String) *graphql.String {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	s := graphql.String(v.ValueString())
+	return &s
+}
+
+// boolFilter returns the variable for an optional boolean filter argument, which
+// is null, and so ignored by the API, when v is not set.
+func boolFilter(v types.Bool) *graphql.Boolean {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	b := graphql.Boolean(v.ValueBool())
+	return &b
+}
+
+// Default timeouts for resource operations that do not configure a timeouts block.
+const (
+	defaultCreateTimeout = 20 * time.Minute
+	defaultReadTimeout   = 5 * time.Minute
+	defaultUpdateTimeout = 20 * time.Minute
+	defaultDeleteTimeout = 5 * time.Minute
+)
+
+// withTimeout bounds ctx by the timeout an operation is configured with (one of
+// the timeouts.Value methods, such as Create), or def when none is set, so every
+// GraphQL call and polling loop of the operation shares the deadline.
+func withTimeout(ctx context.Context, timeout func(context.Context, time.Duration) (time.Duration, diag.Diagnostics), def time.Duration, diags *diag.Diagnostics) (context.Context, context.CancelFunc) {
+	d, timeoutDiags := timeout(ctx, def)
+	diags.Append(timeoutDiags...)
+	return context.WithTimeout(ctx, d)
+}
+
+// stringsFromList copies the elements of an optional list of strings into target,
+// leaving it nil when the list is null or unknown.
+func stringsFromList(ctx context.Context, list types.List, target *[]string) diag.Diagnostics {
+	if list.IsNull() || list.IsUnknown() {
+		return nil
+	}
+	return list.ElementsAs(ctx, target, false)
+}
+
+// stringOneOfValidator validates that a string is one of a fixed set of values.
+type stringOneOfValidator struct {
+	values []string
+}
+
+func (v stringOneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("Value must be one of: %s.", strings.Join(v.values, ", "))
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	quoted := make([]string, len(v.values))
+	for i, value := range v.values {
+		quoted[i] = "`" + value + "`"
+	}
+	return fmt.Sprintf("Value must be one of: %s.", strings.Join(quoted, ", "))
+}
+
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	for _, allowed := range v.values {
+		if value == allowed {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid value",
+		fmt.Sprintf("%q is not valid. %s", value, v.Description(ctx)),
+	)
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pinComponentVersions sets the version of each private component reference in
+// definition whose key is in versions: any map's "component" value with a key and
+// isPublic not true, which covers step actions as well as connections and data
+// sources. It returns the rewritten definition and, sorted, the keys of versions
+// that no reference matched. definition is returned unchanged when versions is
+// empty.
+func pinComponentVersions(definition string, versions map[string]int64) (string, []string, error) {
+	if len(versions) == 0 {
+		return definition, nil, nil
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(definition), &document); err != nil {
+		return "", nil, err
+	}
+
+	pinned := map[string]bool{}
+	if len(document.Content) > 0 {
+		pinComponentReferences(document.Content[0], versions, pinned)
+	}
+	var unmatched []string
+	for key := range versions {
+		if !pinned[key] {
+			unmatched = append(unmatched, key)
+		}
+	}
+	sort.Strings(unmatched)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return "", nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", nil, err
+	}
+	return buf.String(), unmatched, nil
+}
+
+func pinComponentReferences(node *yaml.Node, versions map[string]int64, pinned map[string]bool) {
+	walkPrivateComponentReferences(node, func(component *yaml.Node, key string) bool {
+		if version, ok := versions[key]; ok {
+			setMappingValue(component, "version", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(version, 10)})
+			pinned[key] = true
+		}
+		return true
+	})
+}
+
+// walkPrivateComponentReferences calls visit with the "component" mapping and
+// key of each private component reference under node, stopping early if visit
+// returns false. A reference is private unless its isPublic is true, whether
+// written as a boolean or a string. It reports whether the walk ran to the end.
+func walkPrivateComponentReferences(node *yaml.Node, visit func(component *yaml.Node, key string) bool) bool {
+	if node.Kind == yaml.MappingNode {
+		if component := mappingValue(node, "component"); component != nil && component.Kind == yaml.MappingNode {
+			key, public := mappingValue(component, "key"), mappingValue(component, "isPublic")
+			if key != nil && (public == nil || !strings.EqualFold(public.Value, "true")) {
+				if !visit(component, key.Value) {
+					return false
+				}
+			}
+		}
+	}
+	// Aliases are skipped: the anchored node they refer to is visited where it is
+	// defined.
+	for _, child := range node.Content {
+		if !walkPrivateComponentReferences(child, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// definitionFile is one YAML file of an integration definition split across
+// several, named as errors should refer to it.
+type definitionFile struct {
+	Name    string
+	Content []byte
+}
+
+// readDefinitionFiles reads the named files in order.
+func readDefinitionFiles(paths []string) ([]definitionFile, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("at least one file must be listed")
+	}
+	files := make([]definitionFile, 0, len(paths))
+	for _, p := range paths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, definitionFile{Name: p, Content: content})
+	}
+	return files, nil
+}
+
+// readDefinitionDirectory reads every .yml and .yaml file under dir, including
+// subdirectories, in lexical order of their paths relative to dir, which is also
+// how they are named. Hidden files and directories are skipped.
+func readDefinitionDirectory(dir string) ([]definitionFile, error) {
+	var files []definitionFile
+	err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != dir && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(p); ext != ".yml" && ext != ".yaml" {
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, definitionFile{Name: filepath.ToSlash(name), Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s contains no .yml or .yaml files", dir)
+	}
+	return files, nil
+}
+
+// mergedDefinition is an integration definition assembled from several files.
+// origins maps each node to the file it came from so that problems found in the
+// merged document can be reported against the file to fix.
+type mergedDefinition struct {
+	root    *yaml.Node
+	origins map[*yaml.Node]string
+}
+
+// mergeDefinitionFiles merges the YAML documents of files, in order, into one
+// definition: maps are merged key by key, lists are concatenated, and a scalar
+// may be repeated only with the same value. Each file may hold several documents
+// separated by "---"; empty documents are ignored.
+func mergeDefinitionFiles(files []definitionFile) (*mergedDefinition, []definitionError) {
+	m := &mergedDefinition{origins: map[*yaml.Node]string{}}
+	var errs []definitionError
+	for _, file := range files {
+		decoder := yaml.NewDecoder(bytes.NewReader(file.Content))
+		for {
+			var document yaml.Node
+			err := decoder.Decode(&document)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				syntaxErr := yamlSyntaxError(err)
+				syntaxErr.File = file.Name
+				errs = append(errs, syntaxErr)
+				break
+			}
+			if len(document.Content) == 0 {
+				continue
+			}
+			node := expandAliases(document.Content[0])
+			m.track(node, file.Name)
+			if m.root == nil {
+				m.root = node
+				continue
+			}
+			errs = append(errs, m.merge(m.root, node, "")...)
+		}
+	}
+	if len(errs) == 0 && m.root == nil {
+		errs = append(errs, definitionError{File: files[len(files)-1].Name, Line: 1, Column: 1, Message: "the definition is empty"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return m, nil
+}
+
+func (m *mergedDefinition) track(node *yaml.Node, file string) {
+	m.origins[node] = file
+	for _, child := range node.Content {
+		m.track(child, file)
+	}
+}
+
+func (m *mergedDefinition) merge(dst, src *yaml.Node, path string) []definitionError {
+	switch {
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		var errs []definitionError
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+			existing := mappingValue(dst, key.Value)
+			switch {
+			case existing == nil:
+				dst.Content = append(dst.Content, key, value)
+			case existing.Tag == "!!null":
+				*existing = *value
+				m.origins[existing] = m.origins[value]
+			case value.Tag != "!!null":
+				errs = append(errs, m.merge(existing, value, joinDefinitionPath(path, key.Value))...)
+			}
+		}
+		return errs
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		dst.Content = append(dst.Content, src.Content...)
+		return nil
+	case dst.Kind == yaml.ScalarNode && src.Kind == yaml.ScalarNode && dst.Tag == src.Tag && dst.Value == src.Value:
+		return nil
+	}
+	return []definitionError{{
+		File:    m.origins[src],
+		Line:    src.Line,
+		Column:  src.Column,
+		Path:    path,
+		Message: fmt.Sprintf("%s conflicts with %s at line %d of %s", nodeKindName(src), nodeKindName(dst), dst.Line, m.origins[dst]),
+	}}
+}
+
+// validate checks the merged definition against the embedded schema, locating
+// each problem in the file it came from.
+func (m *mergedDefinition) validate() []definitionError {
+	v := &definitionValidator{definitions: integrationDefinitionSchema.Definitions, origins: m.origins}
+	v.validate(integrationDefinitionSchema, m.root, "")
+	return v.errors
+}
+
+// String renders the merged definition as the single YAML document submitted to
+// importIntegration.
+func (m *mergedDefinition) String() (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m.root); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// definitionHash is the hex SHA-256 of a definition, recorded in state so that
+// any edit to the files it was assembled from shows up in the plan.
+func definitionHash(definition string) string {
+	sum := sha256.Sum256([]byte(definition))
+	return hex.EncodeToString(sum[:])
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// expandAliases returns node with every alias replaced by a copy of the node it
+// refers to and anchors removed, as merging may drop the anchored node an alias
+// in the merged document would need.
+func expandAliases(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	expanded := *node
+	expanded.Anchor = ""
+	expanded.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		expanded.Content[i] = expandAliases(child)
+	}
+	return &expanded
+}
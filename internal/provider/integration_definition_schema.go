@@ -0,0 +1,286 @@
+package provider
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed integration_definition_schema.yaml
+var integrationDefinitionSchemaYAML []byte
+
+// definitionSchema is a node of the schema in integration_definition_schema.yaml,
+// whose header documents each field.
+type definitionSchema struct {
+	Type          schemaTypes                  `yaml:"type"`
+	Description   string                       `yaml:"description"`
+	Required      []string                     `yaml:"required"`
+	RequiredOneOf [][]string                   `yaml:"requiredOneOf"`
+	Properties    map[string]*definitionSchema `yaml:"properties"`
+	Items         *definitionSchema            `yaml:"items"`
+	MinItems      int                          `yaml:"minItems"`
+	Contains      *definitionSchema            `yaml:"contains"`
+	Const         yaml.Node                    `yaml:"const"`
+	Ref           string                       `yaml:"$ref"`
+	Definitions   map[string]*definitionSchema `yaml:"definitions"`
+}
+
+// schemaTypes is a schema's type, written as a single type or a list of them.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = schemaTypes{node.Value}
+		return nil
+	}
+	var types []string
+	if err := node.Decode(&types); err != nil {
+		return err
+	}
+	*t = types
+	return nil
+}
+
+// integrationDefinitionSchema is parsed once; the embedded schema is part of the
+// build, so failing to parse it is a programming error.
+var integrationDefinitionSchema = func() *definitionSchema {
+	var schema definitionSchema
+	if err := yaml.Unmarshal(integrationDefinitionSchemaYAML, &schema); err != nil {
+		panic(fmt.Sprintf("invalid integration definition schema: %s", err))
+	}
+	return &schema
+}()
+
+// definitionError is a problem found validating a definition, located by the line
+// and column of the offending YAML and its path within the document. File names
+// the file the YAML came from when the definition was assembled from several.
+type definitionError struct {
+	File    string
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+func (e definitionError) Error() string {
+	location := fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+	if e.File != "" {
+		location = e.File + ", " + location
+	}
+	if e.Path == "" {
+		return fmt.Sprintf("%s: %s", location, e.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, e.Path, e.Message)
+}
+
+// validateIntegrationDefinition checks a YAML integration definition against the
+// embedded schema, returning every problem found in document order.
+func validateIntegrationDefinition(definition string) []definitionError {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(definition), &document); err != nil {
+		return []definitionError{yamlSyntaxError(err)}
+	}
+	if len(document.Content) == 0 {
+		return []definitionError{{Line: 1, Column: 1, Message: "the definition is empty"}}
+	}
+
+	v := &definitionValidator{definitions: integrationDefinitionSchema.Definitions}
+	v.validate(integrationDefinitionSchema, document.Content[0], "")
+	return v.errors
+}
+
+// yamlSyntaxError converts a YAML parse error, whose message carries the line
+// ("yaml: line 3: ..."), into a definitionError.
+func yamlSyntaxError(err error) definitionError {
+	message := strings.TrimPrefix(err.Error(), "yaml: ")
+	var line int
+	if n, _ := fmt.Sscanf(message, "line %d:", &line); n == 1 {
+		message = strings.TrimSpace(strings.TrimPrefix(message, fmt.Sprintf("line %d:", line)))
+	} else {
+		line = 1
+	}
+	return definitionError{Line: line, Column: 1, Message: "invalid YAML: " + message}
+}
+
+type definitionValidator struct {
+	definitions map[string]*definitionSchema
+	origins     map[*yaml.Node]string
+	errors      []definitionError
+}
+
+func (v *definitionValidator) fail(node *yaml.Node, path, format string, args ...interface{}) {
+	v.errors = append(v.errors, definitionError{
+		File:    v.origins[node],
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *definitionValidator) resolve(schema *definitionSchema) *definitionSchema {
+	for schema.Ref != "" {
+		schema = v.definitions[schema.Ref]
+	}
+	return schema
+}
+
+// matches reports whether node validates against schema, without recording errors.
+func (v *definitionValidator) matches(schema *definitionSchema, node *yaml.Node) bool {
+	probe := &definitionValidator{definitions: v.definitions}
+	probe.validate(schema, node, "")
+	return len(probe.errors) == 0
+}
+
+func (v *definitionValidator) validate(schema *definitionSchema, node *yaml.Node, path string) {
+	schema = v.resolve(schema)
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	if len(schema.Type) > 0 && !nodeHasType(node, schema.Type) {
+		what := schema.Description
+		if what == "" {
+			what = strings.Join(schema.Type, " or ")
+		}
+		v.fail(node, path, "expected %s, got %s", what, nodeKindName(node))
+		return
+	}
+	if schema.Const.Kind != 0 && (node.Kind != yaml.ScalarNode || node.Value != schema.Const.Value) {
+		v.fail(node, path, "must be %s", schema.Const.Value)
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		v.validateMapping(schema, node, path)
+	case yaml.SequenceNode:
+		v.validateSequence(schema, node, path)
+	}
+}
+
+func (v *definitionValidator) validateMapping(schema *definitionSchema, node *yaml.Node, path string) {
+	values := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		values[node.Content[i].Value] = node.Content[i+1]
+	}
+	present := func(key string) bool {
+		value, ok := values[key]
+		return ok && value.Tag != "!!null"
+	}
+
+	for _, key := range schema.Required {
+		if !present(key) {
+			v.fail(node, path, "%s is missing required key %q", describe(schema), key)
+		}
+	}
+	if len(schema.RequiredOneOf) > 0 {
+		satisfied := false
+		for _, keys := range schema.RequiredOneOf {
+			all := true
+			for _, key := range keys {
+				all = all && present(key)
+			}
+			satisfied = satisfied || all
+		}
+		if !satisfied {
+			alternatives := make([]string, len(schema.RequiredOneOf))
+			for i, keys := range schema.RequiredOneOf {
+				alternatives[i] = strings.Join(keys, " and ")
+			}
+			v.fail(node, path, "%s must have %s", describe(schema), strings.Join(alternatives, ", or "))
+		}
+	}
+
+	// Walk keys in document order so errors are reported top to bottom.
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		property, ok := schema.Properties[key]
+		if !ok || value.Tag == "!!null" {
+			continue
+		}
+		v.validate(property, value, joinDefinitionPath(path, key))
+	}
+}
+
+func (v *definitionValidator) validateSequence(schema *definitionSchema, node *yaml.Node, path string) {
+	if len(node.Content) < schema.MinItems {
+		v.fail(node, path, "must have at least %d item(s)", schema.MinItems)
+	}
+	if schema.Items != nil {
+		for i, item := range node.Content {
+			v.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+	if schema.Contains != nil && len(node.Content) > 0 {
+		found := false
+		for _, item := range node.Content {
+			found = found || v.matches(schema.Contains, item)
+		}
+		if !found {
+			v.fail(node, path, "must contain %s", describe(v.resolve(schema.Contains)))
+		}
+	}
+}
+
+func describe(schema *definitionSchema) string {
+	if schema.Description != "" {
+		return schema.Description
+	}
+	return "the value"
+}
+
+func joinDefinitionPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func nodeHasType(node *yaml.Node, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "object":
+			if node.Kind == yaml.MappingNode {
+				return true
+			}
+		case "array":
+			if node.Kind == yaml.SequenceNode {
+				return true
+			}
+		case "string":
+			if node.Kind == yaml.ScalarNode && node.Tag != "!!null" {
+				return true
+			}
+		case "integer":
+			if node.Kind == yaml.ScalarNode && node.Tag == "!!int" {
+				return true
+			}
+		case "boolean":
+			if node.Kind == yaml.ScalarNode && node.Tag == "!!bool" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nodeKindName(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a map"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	switch node.Tag {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "a boolean"
+	case "!!int", "!!float":
+		return "a number"
+	}
+	return fmt.Sprintf("%q", node.Value)
+}
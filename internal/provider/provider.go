@@ -2,13 +2,17 @@ package provider
 
 import (
 	"context"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/prismatic-io/terraform-provider-prismatic/internal/util"
 )
 
 var _ provider.Provider = (*prismaticProvider)(nil)
@@ -29,6 +33,14 @@ type providerModel struct {
 	Token        types.String `tfsdk:"token"`
 	RefreshToken types.String `tfsdk:"refresh_token"`
 	TenantId     types.String `tfsdk:"tenant_id"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait types.Int64  `tfsdk:"retry_max_wait"`
+}
+
+// defaultRetryPolicy applies when max_retries and retry_max_wait are not configured.
+var defaultRetryPolicy = util.RetryPolicy{
+	MaxRetries: 3,
+	MaxWait:    30 * time.Second,
 }
 
 func (p *prismaticProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -58,6 +70,14 @@ func (p *prismaticProvider) Schema(ctx context.Context, req provider.SchemaReque
 				Optional:    true,
 				Description: "The [tenant ID to authenticate against](https://prismatic.io/docs/cli/bash-scripting/#headless-prism-usage-for-cicd-pipelines) when a refresh token grants access to multiple tenants. If omitted, it is left out of the token exchange.",
 			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of times a request that fails with a network error, a `429` or a `5xx` response is retried. Only idempotent requests (queries and uploads) are retried; mutations are not. Defaults to `3`; `0` disables retries.",
+			},
+			"retry_max_wait": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of seconds to wait between retries, including waits requested by a `Retry-After` header. Defaults to `30`.",
+			},
 		},
 	}
 }
@@ -82,24 +102,46 @@ func (p *prismaticProvider) Configure(ctx context.Context, req provider.Configur
 	if token == "" && refreshToken == "" {
 		resp.Diagnostics.AddError("Unable to create a Prismatic client", "Unable to create a Prismatic client without an authorization token or a refresh token. Please either pass in an authorization token or a refresh_token to the Prismatic provider. Optionally, you can set a environment variable, PRISMATIC_TOKEN or PRISMATIC_REFRESH_TOKEN")
 	}
+
+	retryPolicy := defaultRetryPolicy
+	if !config.MaxRetries.IsNull() && !config.MaxRetries.IsUnknown() {
+		retryPolicy.MaxRetries = int(config.MaxRetries.ValueInt64())
+	}
+	if !config.RetryMaxWait.IsNull() && !config.RetryMaxWait.IsUnknown() {
+		retryPolicy.MaxWait = time.Duration(config.RetryMaxWait.ValueInt64()) * time.Second
+	}
+	if retryPolicy.MaxRetries < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("max_retries"), "Invalid retry configuration", "max_retries must not be negative.")
+	}
+	if retryPolicy.MaxWait < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("retry_max_wait"), "Invalid retry configuration", "retry_max_wait must not be negative.")
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	client, err := newGraphQLClient(baseUrl, token, refreshToken, tenantId)
+	transport := util.NewRetryTransport(http.DefaultTransport, retryPolicy)
+	client, err := newGraphQLClient(baseUrl, token, refreshToken, tenantId, transport)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to create a Prismatic client", err.Error())
 		return
 	}
 
-	resp.ResourceData = client
-	resp.DataSourceData = client
+	clients := &prismaticClients{
+		graphql: client,
+		http:    &http.Client{Transport: transport},
+	}
+	resp.ResourceData = clients
+	resp.DataSourceData = clients
 }
 
 func (p *prismaticProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		func() resource.Resource { return &componentResource{} },
+		func() resource.Resource { return &customerResource{} },
+		func() resource.Resource { return &instanceResource{} },
 		func() resource.Resource { return &integrationResource{} },
+		func() resource.Resource { return &integrationVersionResource{} },
 		func() resource.Resource { return &organizationSigningKeyResource{} },
 		func() resource.Resource { return &organizationUserResource{} },
 	}
@@ -109,10 +151,14 @@ func (p *prismaticProvider) DataSources(ctx context.Context) []func() datasource
 	return []func() datasource.DataSource{
 		func() datasource.DataSource { return &authenticatedUserDataSource{} },
 		func() datasource.DataSource { return &componentBundleDataSource{} },
+		func() datasource.DataSource { return &componentDataSource{} },
+		func() datasource.DataSource { return &componentVersionsDataSource{} },
 		func() datasource.DataSource { return &componentsDataSource{} },
+		func() datasource.DataSource { return &integrationDataSource{} },
 		func() datasource.DataSource { return &integrationsDataSource{} },
 		func() datasource.DataSource { return &organizationRolesDataSource{} },
 		func() datasource.DataSource { return &organizationSigningKeyDataSource{} },
+		func() datasource.DataSource { return &userDataSource{} },
 		func() datasource.DataSource { return &usersDataSource{} },
 	}
 }
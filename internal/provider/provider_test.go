@@ -1,11 +1,21 @@
 package provider
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/prismatic-io/terraform-provider-prismatic/internal/cassette"
+	"github.com/prismatic-io/terraform-provider-prismatic/internal/fakeprismatic"
+	"github.com/prismatic-io/terraform-provider-prismatic/internal/util"
 	"github.com/shurcooL/graphql"
 )
 
@@ -15,6 +25,14 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 }
 
 func testAccPreCheck(t *testing.T) {
+	switch mode := cassette.Mode(os.Getenv("PRISMATIC_CASSETTE")); mode {
+	case "":
+	case cassette.ModeRecord, cassette.ModeReplay:
+		testAccUseCassette(t, mode)
+	default:
+		t.Fatalf("PRISMATIC_CASSETTE must be %q or %q, got %q", cassette.ModeRecord, cassette.ModeReplay, mode)
+	}
+
 	if v := os.Getenv("PRISMATIC_URL"); v == "" {
 		t.Fatal("PRISMATIC_URL must be set for acceptance tests")
 	}
@@ -24,6 +42,77 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
+// testAccUseCassette routes the test's API traffic through a cassette in
+// test/data/cassettes named after the test. Recording proxies to the live API in
+// PRISMATIC_URL and saves the cassette if the test passes; replaying serves the
+// saved cassette instead, skipping the test if none has been recorded (with a
+// warning annotation when running in CI).
+func testAccUseCassette(t *testing.T, mode cassette.Mode) {
+	t.Helper()
+
+	path := filepath.Join("..", "..", "test", "data", "cassettes", strings.ReplaceAll(t.Name(), "/", "_")+".json")
+
+	var server *cassette.Server
+	var err error
+	switch mode {
+	case cassette.ModeRecord:
+		if os.Getenv("PRISMATIC_URL") == "" {
+			t.Fatal("PRISMATIC_URL must be set to record cassettes")
+		}
+		server, err = cassette.Record(path, os.Getenv("PRISMATIC_URL"))
+	case cassette.ModeReplay:
+		if _, statErr := os.Stat(path); errors.Is(statErr, fs.ErrNotExist) {
+			if os.Getenv("CI") != "" {
+				// A workflow command, so the skip shows as a warning on the CI run
+				// rather than only in the verbose log.
+				fmt.Printf("::warning title=Missing cassette::%s did not run: no cassette recorded at %s\n", t.Name(), path)
+			}
+			t.Skipf("No cassette recorded at %s; record one with PRISMATIC_CASSETTE=record", path)
+		}
+		server, err = cassette.Replay(path)
+		t.Setenv("PRISMATIC_TOKEN", cassette.ReplayToken)
+		t.Setenv("PRISMATIC_REFRESH_TOKEN", "")
+		t.Setenv("PRISMATIC_TENANT_ID", "")
+	}
+	if err != nil {
+		t.Fatalf("Unable to start cassette %s: %s", path, err)
+	}
+
+	t.Cleanup(func() {
+		server.Close()
+		if t.Failed() {
+			return
+		}
+		if err := server.Save(); err != nil {
+			t.Errorf("Unable to save cassette %s: %s", path, err)
+		}
+	})
+	t.Setenv("PRISMATIC_URL", server.URL)
+}
+
+// testUnitPreCheck points the provider at an in-memory fake Prismatic API for the
+// rest of the test, so resource lifecycles can run with resource.UnitTest and no
+// tenant. The test is skipped unless a Terraform CLI is available locally, as the
+// testing framework would otherwise download one.
+func testUnitPreCheck(t *testing.T) *fakeprismatic.Server {
+	t.Helper()
+
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") == "" && os.Getenv("TF_ACC_TERRAFORM_VERSION") == "" {
+		if _, err := exec.LookPath("terraform"); err != nil {
+			t.Skip("Terraform CLI not found; add it to PATH or set TF_ACC_TERRAFORM_PATH to run lifecycle tests")
+		}
+	}
+
+	server := fakeprismatic.NewServer()
+	t.Cleanup(server.Close)
+
+	t.Setenv("PRISMATIC_URL", server.URL)
+	t.Setenv("PRISMATIC_TOKEN", server.Token)
+	t.Setenv("PRISMATIC_REFRESH_TOKEN", "")
+	t.Setenv("PRISMATIC_TENANT_ID", "")
+	return server
+}
+
 // testAccGraphQLClient builds a client from the same environment variables the
 // provider reads, for use in CheckDestroy functions (which run outside provider
 // configuration).
@@ -33,5 +122,6 @@ func testAccGraphQLClient() (*graphql.Client, error) {
 		os.Getenv("PRISMATIC_TOKEN"),
 		os.Getenv("PRISMATIC_REFRESH_TOKEN"),
 		os.Getenv("PRISMATIC_TENANT_ID"),
+		util.NewRetryTransport(http.DefaultTransport, defaultRetryPolicy),
 	)
 }
@@ -5,36 +5,61 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/prismatic-io/terraform-provider-prismatic/internal/util"
 	"github.com/shurcooL/graphql"
+	"gopkg.in/yaml.v3"
 )
 
 var (
-	_ resource.Resource              = (*componentResource)(nil)
-	_ resource.ResourceWithConfigure = (*componentResource)(nil)
+	_ resource.Resource               = (*componentResource)(nil)
+	_ resource.ResourceWithConfigure  = (*componentResource)(nil)
+	_ resource.ResourceWithModifyPlan = (*componentResource)(nil)
 )
 
 type componentResource struct {
-	client *graphql.Client
+	client     *graphql.Client
+	httpClient *http.Client
 }
 
 type componentResourceModel struct {
-	Id              types.String `tfsdk:"id"`
-	Key             types.String `tfsdk:"key"`
-	Label           types.String `tfsdk:"label"`
-	Description     types.String `tfsdk:"description"`
-	BundleDirectory types.String `tfsdk:"bundle_directory"`
-	BundlePath      types.String `tfsdk:"bundle_path"`
-	Signature       types.String `tfsdk:"signature"`
+	Id               types.String   `tfsdk:"id"`
+	Key              types.String   `tfsdk:"key"`
+	Label            types.String   `tfsdk:"label"`
+	Description      types.String   `tfsdk:"description"`
+	BundleDirectory  types.String   `tfsdk:"bundle_directory"`
+	BundlePath       types.String   `tfsdk:"bundle_path"`
+	Signature        types.String   `tfsdk:"signature"`
+	ManifestPath     types.String   `tfsdk:"manifest_path"`
+	VersionNumber    types.Int64    `tfsdk:"version_number"`
+	VersionCreatedAt types.String   `tfsdk:"version_created_at"`
+	Public           types.Bool     `tfsdk:"public"`
+	CustomerId       types.String   `tfsdk:"customer_id"`
+	DeletionPolicy   types.String   `tfsdk:"deletion_policy"`
+	Timeouts         timeouts.Value `tfsdk:"timeouts"`
+}
+
+const (
+	componentDeletionPolicyAbandon = "abandon"
+	componentDeletionPolicyDelete  = "delete"
+)
+
+type DeleteComponentInput struct {
+	Id graphql.ID `json:"id"`
 }
 
 func (r *componentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -71,24 +96,62 @@ func (r *componentResource) Schema(ctx context.Context, req resource.SchemaReque
 			},
 			"signature": schema.StringAttribute{
 				Required:    true,
-				Description: "Bundle signature. Reference the results of the 'Component Bundle' data source.",
+				Description: "Bundle signature. Reference the results of the 'Component Bundle' data source. It is recorded with the published Component; if the Component is republished outside Terraform with a different bundle, the next plan updates it to this bundle again. Signatures are a SHA-256 of the bundled files' contents; a Component published with a provider version that signed bundles differently is republished once on the first apply after upgrading (see the CHANGELOG).",
+			},
+			"version_number": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The version number of the published Component",
+			},
+			"version_created_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "When the version of the Component was published",
+			},
+			"public": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the Component is public, available to all Prismatic organizations",
+			},
+			"customer_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the Customer the Component belongs to, or null for a Component of the Organization",
+			},
+			"deletion_policy": schema.StringAttribute{
+				Optional:    true,
+				Description: "What destroying the resource does to the Component: `abandon` (the default) only removes it from Terraform state and leaves the Component in Prismatic, while `delete` deletes the Component. Deletion is refused while Integrations still use the Component.",
+				Validators: []validator.String{
+					stringOneOfValidator{values: []string{componentDeletionPolicyAbandon, componentDeletionPolicyDelete}},
+				},
+			},
+			"manifest_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path, relative to `bundle_directory`, of a JSON manifest of the Component: the serialized default export of the bundle, as written by `node -e 'console.log(JSON.stringify(require(\".\").default))'`. When set, the definition and actions are read from the manifest and Node.js is not required to publish. When omitted, the bundle is evaluated with `node`.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
 func (r *componentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	r.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+	r.httpClient = httpClientFromProviderData(req.ProviderData, &resp.Diagnostics)
 }
 
 func (r *componentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan componentResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	componentId, err := publishComponent(ctx, r.client, plan.BundleDirectory.ValueString(), plan.BundlePath.ValueString())
+	componentId, err := publishComponent(ctx, r.client, r.httpClient, plan.BundleDirectory.ValueString(), plan.BundlePath.ValueString(), plan.ManifestPath.ValueString(), plan.Signature.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to publish component", err.Error())
 		return
@@ -117,6 +180,8 @@ func (r *componentResource) Create(ctx context.Context, req resource.CreateReque
 func (r *componentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state componentResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -129,7 +194,9 @@ func (r *componentResource) Read(ctx context.Context, req resource.ReadRequest,
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	published := updated.Signature
 	updated.copyBundleInputsFrom(state)
+	updated.Signature = refreshedSignature(state.Signature, published)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, updated)...)
 }
@@ -139,20 +206,26 @@ func (r *componentResource) Update(ctx context.Context, req resource.UpdateReque
 	var state componentResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// publishComponent upserts by key. Re-read by the prior id so the resource id
-	// stays immutable across updates rather than adopting the id the publish returns.
-	if _, err := publishComponent(ctx, r.client, plan.BundleDirectory.ValueString(), plan.BundlePath.ValueString()); err != nil {
-		resp.Diagnostics.AddError("Unable to publish component", err.Error())
-		return
-	}
+	// Settings that only affect the provider, such as deletion_policy, do not
+	// warrant publishing a new version.
+	if plan.bundleChanged(state) {
+		// publishComponent upserts by key. Re-read by the prior id so the resource id
+		// stays immutable across updates rather than adopting the id the publish returns.
+		if _, err := publishComponent(ctx, r.client, r.httpClient, plan.BundleDirectory.ValueString(), plan.BundlePath.ValueString(), plan.ManifestPath.ValueString(), plan.Signature.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unable to publish component", err.Error())
+			return
+		}
 
-	if err := waitForComponent(ctx, r.client, state.Id.ValueString()); err != nil {
-		resp.Diagnostics.AddError("Unable to publish component", err.Error())
-		return
+		if err := waitForComponent(ctx, r.client, state.Id.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unable to publish component", err.Error())
+			return
+		}
 	}
 
 	updated := r.read(ctx, state.Id.ValueString(), &resp.Diagnostics)
@@ -168,22 +241,150 @@ func (r *componentResource) Update(ctx context.Context, req resource.UpdateReque
 	resp.Diagnostics.Append(resp.State.Set(ctx, updated)...)
 }
 
+// ModifyPlan keeps the published version's attributes when an update does not
+// publish, such as one changing only deletion_policy, so that they are not
+// planned as unknown and Integrations pinned to version_number are not updated.
+func (r *componentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan componentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.bundleChanged(state) {
+		return
+	}
+
+	plan.copyPublishedFrom(state)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
 func (r *componentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	// Only clear state since it seems unlikely that the consequences of a delete are
-	// desired, particularly in resource "tainted" situations.
-	resp.State.RemoveResource(ctx)
+	var state componentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Unless deletion is opted into, only clear state since it seems unlikely that
+	// the consequences of a delete are desired, particularly in resource "tainted"
+	// situations.
+	if state.DeletionPolicy.ValueString() != componentDeletionPolicyDelete {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	referencing, err := integrationsUsingComponent(ctx, r.client, state.Key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to delete component", err.Error())
+		return
+	}
+	if len(referencing) > 0 {
+		resp.Diagnostics.AddError(
+			"Component is still in use",
+			fmt.Sprintf("Component %q cannot be deleted while Integrations use it. Remove it from these Integrations first, or set deletion_policy to %q:\n  - %s",
+				state.Key.ValueString(), componentDeletionPolicyAbandon, strings.Join(referencing, "\n  - ")),
+		)
+		return
+	}
+
+	var mutation struct {
+		DeleteComponent struct {
+			Component struct {
+				Id graphql.ID
+			}
+			Errors util.GqlErrors
+		} `graphql:"deleteComponent(input: $input)"`
+	}
+
+	variables := map[string]interface{}{
+		"input": DeleteComponentInput{
+			Id: graphql.ID(state.Id.ValueString()),
+		},
+	}
+
+	if err := r.client.Mutate(ctx, &mutation, variables); err != nil {
+		if isRecordNotFound(err) {
+			return
+		}
+		resp.Diagnostics.AddError("Unable to delete component", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(gqlErrorDiagnostics(mutation.DeleteComponent.Errors)...)
+}
+
+// integrationsUsingComponent lists, as "name (id)", the Integrations whose
+// definitions reference the private Component with the given key.
+func integrationsUsingComponent(ctx context.Context, client *graphql.Client, key string) ([]string, error) {
+	variables := map[string]interface{}{
+		"after": (*graphql.String)(nil),
+	}
+
+	var referencing []string
+	for {
+		var query struct {
+			Integrations struct {
+				Nodes []struct {
+					Id         graphql.ID
+					Name       graphql.String
+					Definition graphql.String
+				}
+				PageInfo pageInfo
+			} `graphql:"integrations(after: $after)"`
+		}
+		if err := client.Query(ctx, &query, variables); err != nil {
+			return nil, err
+		}
+
+		for _, integration := range query.Integrations.Nodes {
+			if definitionUsesComponent(string(integration.Definition), key) {
+				referencing = append(referencing, fmt.Sprintf("%s (%s)", integration.Name, integration.Id.(string)))
+			}
+		}
+		if !query.Integrations.PageInfo.HasNextPage || query.Integrations.PageInfo.EndCursor == nil {
+			break
+		}
+		variables["after"] = query.Integrations.PageInfo.EndCursor
+	}
+	return referencing, nil
+}
+
+// definitionUsesComponent reports whether an Integration's YAML definition
+// references the private Component with the given key, from a step action, a
+// trigger or a connection config variable. Definitions that cannot be parsed are
+// treated as not referencing it.
+func definitionUsesComponent(definition string, key string) bool {
+	var document yaml.Node
+	if yaml.Unmarshal([]byte(definition), &document) != nil || len(document.Content) == 0 {
+		return false
+	}
+	return !walkPrivateComponentReferences(document.Content[0], func(_ *yaml.Node, referenced string) bool {
+		return referenced != key
+	})
 }
 
 // read queries the Component by id and maps its server-owned fields into a model,
-// returning nil if the Component no longer exists. The bundle inputs are not part of
-// the API response, so the caller restores them with copyBundleInputsFrom.
+// returning nil if the Component no longer exists. Signature holds the signature
+// the Component was published with. The bundle inputs are not part of the API
+// response, so the caller restores them with copyBundleInputsFrom.
 func (r *componentResource) read(ctx context.Context, id string, diags *diag.Diagnostics) *componentResourceModel {
 	var query struct {
 		Component struct {
-			Id          graphql.ID
-			Key         graphql.String
-			Label       graphql.String
-			Description graphql.String
+			Id               graphql.ID
+			Key              graphql.String
+			Label            graphql.String
+			Description      graphql.String
+			Signature        graphql.String
+			VersionNumber    graphql.Int
+			VersionCreatedAt graphql.String
+			Public           graphql.Boolean
+			Customer         struct {
+				Id graphql.ID
+			}
 		} `graphql:"component(id: $id)"`
 	}
 	variables := map[string]interface{}{
@@ -197,11 +398,21 @@ func (r *componentResource) read(ctx context.Context, id string, diags *diag.Dia
 		return nil
 	}
 
+	customerId := types.StringNull()
+	if id, ok := query.Component.Customer.Id.(string); ok {
+		customerId = types.StringValue(id)
+	}
+
 	return &componentResourceModel{
-		Id:          types.StringValue(query.Component.Id.(string)),
-		Key:         types.StringValue(string(query.Component.Key)),
-		Label:       types.StringValue(string(query.Component.Label)),
-		Description: types.StringValue(string(query.Component.Description)),
+		Id:               types.StringValue(query.Component.Id.(string)),
+		Key:              types.StringValue(string(query.Component.Key)),
+		Label:            types.StringValue(string(query.Component.Label)),
+		Description:      types.StringValue(string(query.Component.Description)),
+		Signature:        types.StringValue(string(query.Component.Signature)),
+		VersionNumber:    types.Int64Value(int64(query.Component.VersionNumber)),
+		VersionCreatedAt: types.StringValue(string(query.Component.VersionCreatedAt)),
+		Public:           types.BoolValue(bool(query.Component.Public)),
+		CustomerId:       customerId,
 	}
 }
 
@@ -211,12 +422,48 @@ func (m *componentResourceModel) copyBundleInputsFrom(src componentResourceModel
 	m.BundleDirectory = src.BundleDirectory
 	m.BundlePath = src.BundlePath
 	m.Signature = src.Signature
+	m.ManifestPath = src.ManifestPath
+	m.DeletionPolicy = src.DeletionPolicy
+	m.Timeouts = src.Timeouts
+}
+
+// copyPublishedFrom carries the attributes of the published version over from src.
+func (m *componentResourceModel) copyPublishedFrom(src componentResourceModel) {
+	m.Id = src.Id
+	m.Key = src.Key
+	m.Label = src.Label
+	m.Description = src.Description
+	m.VersionNumber = src.VersionNumber
+	m.VersionCreatedAt = src.VersionCreatedAt
+	m.Public = src.Public
+	m.CustomerId = src.CustomerId
+}
+
+// bundleChanged reports whether any input that determines the published Component
+// differs from prior.
+func (m componentResourceModel) bundleChanged(prior componentResourceModel) bool {
+	return !m.Signature.Equal(prior.Signature) ||
+		!m.BundleDirectory.Equal(prior.BundleDirectory) ||
+		!m.BundlePath.Equal(prior.BundlePath) ||
+		!m.ManifestPath.Equal(prior.ManifestPath)
+}
+
+// refreshedSignature returns the signature to record on Read. If the Component was
+// republished with a different bundle, the published signature is recorded so it
+// no longer matches the configuration and the plan republishes the configured
+// bundle. Components published without a signature cannot be compared, so the
+// prior signature is kept.
+func refreshedSignature(prior, published types.String) types.String {
+	if published.IsNull() || published.ValueString() == "" {
+		return prior
+	}
+	return published
 }
 
 // waitForComponent polls until the Component with the given id is queryable,
-// tolerating the brief "not found" window after a publish.
+// tolerating the brief "not found" window after a publish, for as long as ctx
+// allows.
 func waitForComponent(ctx context.Context, client *graphql.Client, id string) error {
-	deadline := time.Now().Add(2 * time.Minute)
 	for {
 		var query struct {
 			Component struct {
@@ -228,15 +475,15 @@ func waitForComponent(ctx context.Context, client *graphql.Client, id string) er
 		if err == nil {
 			return nil
 		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("component %q not yet available after publish: %w", id, ctx.Err())
+		}
 		if !isRecordNotFound(err) {
 			return err
 		}
-		if time.Now().After(deadline) {
-			return fmt.Errorf("component %q not yet available after publish", id)
-		}
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return fmt.Errorf("component %q not yet available after publish: %w", id, ctx.Err())
 		case <-time.After(5 * time.Second):
 		}
 	}
@@ -264,23 +511,59 @@ func readComponentBundle(bundlePath string) (*PublishComponentInput, error) {
 		return nil, fmt.Errorf("node exec failed: %s (%s)", err, errStr)
 	}
 
-	var result map[string]interface{}
-	err = json.Unmarshal(stdout.Bytes(), &result)
+	return parseComponentDefinition(stdout.Bytes())
+}
+
+// readComponentManifest reads the definition and actions from a manifest in the
+// bundle directory instead of evaluating the bundle, so no node is needed. The
+// manifest is the JSON serialization of the bundle's default export.
+func readComponentManifest(bundlePath string, manifestPath string) (*PublishComponentInput, error) {
+	content, err := os.ReadFile(filepath.Join(bundlePath, manifestPath))
 	if err != nil {
 		return nil, err
 	}
 
+	input, err := parseComponentDefinition(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid component manifest %q: %w", manifestPath, err)
+	}
+	return input, nil
+}
+
+// parseComponentDefinition splits a JSON component definition into the definition
+// and actions for the publishComponent mutation. Actions are ordered by key so the
+// mutation input does not depend on map iteration order.
+func parseComponentDefinition(content []byte) (*PublishComponentInput, error) {
+	var result map[string]interface{}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, err
+	}
+
 	var actionInputs []interface{}
 	if val, ok := result["actions"]; ok {
-		actionsMap := val.(map[string]interface{})
-		for _, v := range actionsMap {
-			actionInputs = append(actionInputs, v)
+		actionsMap, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("component actions must be an object keyed by action key")
+		}
+		keys := make([]string, 0, len(actionsMap))
+		for k := range actionsMap {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			actionInputs = append(actionInputs, actionsMap[k])
 		}
 	}
 
 	definition := result
 	delete(definition, "actions")
 
+	if display, ok := definition["display"].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("component definition is missing display")
+	} else if _, ok := display["iconPath"].(string); !ok {
+		return nil, fmt.Errorf("component definition is missing display.iconPath")
+	}
+
 	input := PublishComponentInput{
 		Definition: definition,
 		Actions:    actionInputs,
@@ -288,8 +571,14 @@ func readComponentBundle(bundlePath string) (*PublishComponentInput, error) {
 	return &input, nil
 }
 
-func publishComponent(ctx context.Context, client *graphql.Client, bundleDirectory string, packagePath string) (string, error) {
-	bundle, err := readComponentBundle(bundleDirectory)
+func publishComponent(ctx context.Context, client *graphql.Client, httpClient *http.Client, bundleDirectory string, packagePath string, manifestPath string, signature string) (string, error) {
+	var bundle *PublishComponentInput
+	var err error
+	if manifestPath != "" {
+		bundle, err = readComponentManifest(bundleDirectory, manifestPath)
+	} else {
+		bundle, err = readComponentBundle(bundleDirectory)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -309,6 +598,7 @@ func publishComponent(ctx context.Context, client *graphql.Client, bundleDirecto
 		"input": PublishComponentInput{
 			Definition: bundle.Definition,
 			Actions:    bundle.Actions,
+			Signature:  graphql.String(signature),
 		},
 	}
 
@@ -318,11 +608,11 @@ func publishComponent(ctx context.Context, client *graphql.Client, bundleDirecto
 
 	definitionDisplay := bundle.Definition["display"].(map[string]interface{})
 	iconPath := path.Join(bundleDirectory, definitionDisplay["iconPath"].(string))
-	if err := util.UploadFile(iconPath, string(mutation.PublishComponent.PublishResult.IconUploadUrl), "image/png"); err != nil {
+	if err := util.UploadFile(ctx, httpClient, iconPath, string(mutation.PublishComponent.PublishResult.IconUploadUrl), "image/png"); err != nil {
 		return "", err
 	}
 
-	if err := util.UploadFile(packagePath, string(mutation.PublishComponent.PublishResult.PackageUploadUrl), "application/zip"); err != nil {
+	if err := util.UploadFile(ctx, httpClient, packagePath, string(mutation.PublishComponent.PublishResult.PackageUploadUrl), "application/zip"); err != nil {
 		return "", err
 	}
 
@@ -332,4 +622,5 @@ func publishComponent(ctx context.Context, client *graphql.Client, bundleDirecto
 type PublishComponentInput struct {
 	Definition map[string]interface{} `json:"definition" graphql:"DefinitionInput!"`
 	Actions    []interface{}          `json:"actions" graphql:"[ActionDefinitionInput]!"`
+	Signature  graphql.String         `json:"signature,omitempty"`
 }
@@ -35,6 +35,47 @@ resource "prismatic_component" "component" {
 					resource.TestCheckResourceAttr(resourceName, "key", expectedKey),
 					resource.TestCheckResourceAttr(resourceName, "label", expectedLabel),
 					resource.TestCheckResourceAttr(resourceName, "description", expectedDescription),
+					resource.TestCheckResourceAttr("data.prismatic_component_bundle.bundle", "file_hashes.%", "3"),
+					resource.TestCheckResourceAttrSet("data.prismatic_component_bundle.bundle", "file_hashes.index.js"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceComponent_manifest(t *testing.T) {
+	resourceName := "prismatic_component.component"
+	config := `
+data "prismatic_component_bundle" "bundle" {
+    bundle_directory = "../../test/data/component/code"
+    bundle_path = "../../test/data/component/bundle.zip"
+}
+
+resource "prismatic_component" "component" {
+    bundle_directory = data.prismatic_component_bundle.bundle.bundle_directory
+    bundle_path = data.prismatic_component_bundle.bundle.bundle_path
+    signature = data.prismatic_component_bundle.bundle.signature
+    manifest_path = "component.json"
+    deletion_policy = "delete"
+
+    timeouts {
+        create = "10m"
+    }
+}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "key", "componentKey"),
+					resource.TestCheckResourceAttr(resourceName, "manifest_path", "component.json"),
+					resource.TestCheckResourceAttrSet(resourceName, "version_number"),
+					resource.TestCheckResourceAttr(resourceName, "deletion_policy", "delete"),
+					resource.TestCheckResourceAttr(resourceName, "timeouts.create", "10m"),
 				),
 			},
 		},
@@ -1,6 +1,21 @@
 package provider
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
 
 func TestReadComponentBundle(t *testing.T) {
 	result, err := readComponentBundle("../../test/data/component/code/")
@@ -11,3 +26,333 @@ func TestReadComponentBundle(t *testing.T) {
 		t.Fatalf("Received nil result from bundle read")
 	}
 }
+
+func TestReadComponentManifest(t *testing.T) {
+	result, err := readComponentManifest("../../test/data/component/code/", "component.json")
+	if err != nil {
+		t.Fatalf("Failed to read component manifest: %s", err)
+	}
+	if result.Definition["key"] != "componentKey" {
+		t.Errorf("got key %v, want componentKey", result.Definition["key"])
+	}
+	if _, ok := result.Definition["actions"]; ok {
+		t.Error("actions should be split out of the definition")
+	}
+	if len(result.Actions) != 1 {
+		t.Fatalf("got %d actions, want 1", len(result.Actions))
+	}
+
+	if _, err := exec.LookPath("node"); err != nil {
+		return
+	}
+	fromNode, err := readComponentBundle("../../test/data/component/code/")
+	if err != nil {
+		t.Fatalf("Failed to read component bundle: %s", err)
+	}
+	if !reflect.DeepEqual(result, fromNode) {
+		t.Errorf("manifest input %v differs from evaluated bundle %v", result, fromNode)
+	}
+}
+
+func TestParseComponentDefinition(t *testing.T) {
+	input, err := parseComponentDefinition([]byte(`{
+		"key": "c",
+		"display": {"label": "C", "iconPath": "icon.png"},
+		"actions": {"b": {"key": "b"}, "a": {"key": "a"}, "c": {"key": "c"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, action := range input.Actions {
+		keys = append(keys, action.(map[string]interface{})["key"].(string))
+	}
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("actions are ordered %v, want sorted by key", keys)
+	}
+
+	for name, content := range map[string]string{
+		"not json":        `module.exports = {}`,
+		"actions list":    `{"display": {"iconPath": "icon.png"}, "actions": []}`,
+		"missing display": `{"key": "c"}`,
+		"missing icon":    `{"key": "c", "display": {"label": "C"}}`,
+	} {
+		if _, err := parseComponentDefinition([]byte(content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRefreshedSignature(t *testing.T) {
+	cases := []struct {
+		name      string
+		prior     types.String
+		published types.String
+		want      types.String
+	}{
+		{"unchanged", types.StringValue("abc"), types.StringValue("abc"), types.StringValue("abc")},
+		{"republished elsewhere", types.StringValue("abc"), types.StringValue("def"), types.StringValue("def")},
+		{"published without signature", types.StringValue("abc"), types.StringValue(""), types.StringValue("abc")},
+		{"signature unavailable", types.StringValue("abc"), types.StringNull(), types.StringValue("abc")},
+	}
+	for _, tc := range cases {
+		if got := refreshedSignature(tc.prior, tc.published); !got.Equal(tc.want) {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDefinitionUsesComponent(t *testing.T) {
+	definition := `
+definitionVersion: 7
+name: Uses private component
+requiredConfigVars:
+  - key: Connection
+    dataType: connection
+    connection:
+      component:
+        key: connection-owner
+        isPublic: false
+      key: oauth
+flows:
+  - name: Flow 1
+    steps:
+      - name: Trigger
+        isTrigger: true
+        action:
+          component:
+            key: webhook-triggers
+            version: LATEST
+            isPublic: true
+          key: webhook
+      - name: Step
+        action:
+          component:
+            key: componentKey
+            version: LATEST
+            isPublic: false
+          key: actionKey
+      - name: Quoted
+        action:
+          component:
+            key: quoted-public
+            version: LATEST
+            isPublic: "true"
+          key: actionKey
+`
+	cases := []struct {
+		key  string
+		want bool
+	}{
+		{"componentKey", true},
+		{"connection-owner", true},
+		{"webhook-triggers", false},
+		{"quoted-public", false},
+		{"unused", false},
+	}
+	for _, tc := range cases {
+		if got := definitionUsesComponent(definition, tc.key); got != tc.want {
+			t.Errorf("definitionUsesComponent(%q) = %v, want %v", tc.key, got, tc.want)
+		}
+	}
+	if definitionUsesComponent("not: [valid", "componentKey") {
+		t.Error("unparseable definitions should not match")
+	}
+}
+
+func TestComponentBundleChanged(t *testing.T) {
+	prior := componentResourceModel{
+		BundleDirectory: types.StringValue("code"),
+		BundlePath:      types.StringValue("bundle.zip"),
+		Signature:       types.StringValue("abc"),
+		ManifestPath:    types.StringNull(),
+		DeletionPolicy:  types.StringNull(),
+	}
+
+	plan := prior
+	plan.DeletionPolicy = types.StringValue(componentDeletionPolicyDelete)
+	if plan.bundleChanged(prior) {
+		t.Error("changing deletion_policy should not republish")
+	}
+
+	plan.Signature = types.StringValue("def")
+	if !plan.bundleChanged(prior) {
+		t.Error("changing signature should republish")
+	}
+}
+
+func TestUnitResourceComponent_lifecycle(t *testing.T) {
+	server := testUnitPreCheck(t)
+
+	resourceName := "prismatic_component.component"
+	config := `
+data "prismatic_component_bundle" "bundle" {
+    bundle_directory = "../../test/data/component/code"
+    bundle_path = "../../test/data/component/bundle.zip"
+}
+
+resource "prismatic_component" "component" {
+    bundle_directory = data.prismatic_component_bundle.bundle.bundle_directory
+    bundle_path = data.prismatic_component_bundle.bundle.bundle_path
+    signature = data.prismatic_component_bundle.bundle.signature
+    manifest_path = "component.json"
+    deletion_policy = "delete"
+}
+
+data "prismatic_component_versions" "versions" {
+    key = prismatic_component.component.key
+    depends_on = [prismatic_component.component]
+}`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testUnitCheckComponentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "key", "componentKey"),
+					resource.TestCheckResourceAttr(resourceName, "label", "Component label"),
+					resource.TestCheckResourceAttr(resourceName, "version_number", "1"),
+					resource.TestCheckResourceAttrSet(resourceName, "version_created_at"),
+					resource.TestCheckResourceAttr(resourceName, "public", "false"),
+					resource.TestCheckNoResourceAttr(resourceName, "customer_id"),
+					resource.TestCheckResourceAttr(componentVersionsDataSourceName, "versions.#", "1"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// Publishing outside Terraform is detected on refresh and reverted.
+			{
+				PreConfig: func() {
+					if err := server.RepublishComponent("componentKey", "published-elsewhere"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "signature", "data.prismatic_component_bundle.bundle", "signature"),
+					resource.TestCheckResourceAttr(resourceName, "version_number", "3"),
+					resource.TestCheckResourceAttr(componentVersionsDataSourceName, "versions.#", "3"),
+					resource.TestCheckResourceAttr(componentVersionsDataSourceName, "versions.0.version_number", "3"),
+					resource.TestCheckResourceAttrPair(componentVersionsDataSourceName, "versions.0.version_created_at", resourceName, "version_created_at"),
+					resource.TestCheckResourceAttr(componentVersionsDataSourceName, "versions.1.signature", "published-elsewhere"),
+					resource.TestCheckResourceAttr(componentVersionsDataSourceName, "versions.2.version_number", "1"),
+				),
+			},
+			// Changing only deletion_policy does not publish, so the version stays known.
+			{
+				Config: strings.Replace(config, `deletion_policy = "delete"`, `deletion_policy = "abandon"`, 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue(resourceName, tfjsonpath.New("version_number"), knownvalue.Int64Exact(3)),
+					},
+				},
+			},
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr(resourceName, "version_number", "3"),
+			},
+		},
+	})
+}
+
+func TestUnitResourceComponent_deleteInUse(t *testing.T) {
+	server := testUnitPreCheck(t)
+	// A page per integration, so the referencing integration is on page two.
+	server.SetPageSize(1)
+
+	config := func(deletionPolicy string) string {
+		return fmt.Sprintf(`
+data "prismatic_component_bundle" "bundle" {
+    bundle_directory = "../../test/data/component/code"
+    bundle_path = "../../test/data/component/bundle.zip"
+}
+
+resource "prismatic_integration" "unrelated" {
+  definition = <<EOF
+%s
+EOF
+}
+
+resource "prismatic_integration" "uses" {
+  definition = <<EOF
+name: Uses componentKey
+flows:
+  - name: Flow 1
+    steps:
+      - name: Trigger
+        isTrigger: true
+        action:
+          component: {key: webhook-triggers, version: LATEST, isPublic: true}
+          key: webhook
+      - name: Step
+        action:
+          component: {key: componentKey, version: LATEST, isPublic: false}
+          key: actionKey
+EOF
+  depends_on = [prismatic_integration.unrelated]
+}
+
+# Depending on the integrations makes destroy delete the component first.
+resource "prismatic_component" "component" {
+    bundle_directory = data.prismatic_component_bundle.bundle.bundle_directory
+    bundle_path = data.prismatic_component_bundle.bundle.bundle_path
+    signature = data.prismatic_component_bundle.bundle.signature
+    manifest_path = "component.json"
+    deletion_policy = %q
+    depends_on = [prismatic_integration.uses]
+}`, baseDefinition, deletionPolicy)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(componentDeletionPolicyDelete),
+			},
+			{
+				Config:      config(componentDeletionPolicyDelete),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`(?s)Component is still in use.*Uses componentKey`),
+			},
+			{
+				Config: config(componentDeletionPolicyAbandon),
+			},
+		},
+	})
+}
+
+func testUnitCheckComponentDestroy(s *terraform.State) error {
+	client, err := testAccGraphQLClient()
+	if err != nil {
+		return err
+	}
+
+	var query struct {
+		Components struct {
+			Nodes []struct {
+				Key string
+			}
+		}
+	}
+	if err := client.Query(context.Background(), &query, nil); err != nil {
+		return err
+	}
+	for _, component := range query.Components.Nodes {
+		if component.Key == "componentKey" {
+			return fmt.Errorf("component %q still exists", component.Key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,422 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/prismatic-io/terraform-provider-prismatic/internal/util"
+	"github.com/shurcooL/graphql"
+)
+
+var (
+	_ resource.Resource                = (*customerResource)(nil)
+	_ resource.ResourceWithConfigure   = (*customerResource)(nil)
+	_ resource.ResourceWithImportState = (*customerResource)(nil)
+)
+
+// customerExternalIdImportPrefix marks an import ID as a customer external ID
+// rather than a Prismatic ID.
+const customerExternalIdImportPrefix = "external_id:"
+
+type customerResource struct {
+	client *graphql.Client
+}
+
+type customerResourceModel struct {
+	Id          types.String   `tfsdk:"id"`
+	Name        types.String   `tfsdk:"name"`
+	Description types.String   `tfsdk:"description"`
+	ExternalId  types.String   `tfsdk:"external_id"`
+	Labels      types.Set      `tfsdk:"labels"`
+	AvatarUrl   types.String   `tfsdk:"avatar_url"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *customerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_customer"
+}
+
+func (r *customerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage Customers in Prismatic.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The unique identifier of the customer.",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the customer.",
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "The description of the customer.",
+			},
+			"external_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "An external ID for mapping the customer to external systems. Customers can be imported by external ID using an import ID of the form `external_id:<value>`.",
+			},
+			"labels": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				// Computed only to carry the Default, so an omitted set plans as empty
+				// and matches the empty list the API returns.
+				Computed:    true,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+				Description: "Labels applied to the customer.",
+			},
+			"avatar_url": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The URL of the customer's avatar image.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *customerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *customerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan customerResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mutation struct {
+		CreateCustomer struct {
+			Customer struct {
+				Id graphql.ID
+			}
+			Errors util.GqlErrors
+		} `graphql:"createCustomer(input: $input)"`
+	}
+
+	input := CreateCustomerInput{
+		Name:        graphql.String(plan.Name.ValueString()),
+		Description: graphql.String(plan.Description.ValueString()),
+		ExternalId:  graphql.String(plan.ExternalId.ValueString()),
+		Labels:      labelsFromSet(ctx, plan.Labels, &resp.Diagnostics),
+	}
+	if !plan.AvatarUrl.IsNull() && !plan.AvatarUrl.IsUnknown() {
+		input.AvatarUrl = graphql.String(plan.AvatarUrl.ValueString())
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	variables := map[string]interface{}{
+		"input": input,
+	}
+
+	if err := r.client.Mutate(ctx, &mutation, variables); err != nil {
+		resp.Diagnostics.AddError("Unable to create customer", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(gqlErrorDiagnostics(mutation.CreateCustomer.Errors)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := mutation.CreateCustomer.Customer.Id.(string)
+
+	state := r.read(ctx, id, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state == nil {
+		resp.Diagnostics.AddError("Unable to read customer", "Customer was created but could not be found.")
+		return
+	}
+	state.Timeouts = plan.Timeouts
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *customerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state customerResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	newState := r.read(ctx, state.Id.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if newState == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	newState.Timeouts = state.Timeouts
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+// read fetches a customer by id and maps it to a model, returning nil if the
+// customer no longer exists.
+func (r *customerResource) read(ctx context.Context, id string, diags *diag.Diagnostics) *customerResourceModel {
+	var query struct {
+		Customer struct {
+			Id          graphql.ID
+			Name        graphql.String
+			Description graphql.String
+			ExternalId  graphql.String
+			Labels      []graphql.String
+			AvatarUrl   graphql.String
+		} `graphql:"customer(id: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphql.ID(id),
+	}
+
+	if err := r.client.Query(ctx, &query, variables); err != nil {
+		if isRecordNotFound(err) {
+			return nil
+		}
+		diags.AddError("Unable to read customer", err.Error())
+		return nil
+	}
+
+	labels := make([]attr.Value, 0, len(query.Customer.Labels))
+	for _, label := range query.Customer.Labels {
+		labels = append(labels, types.StringValue(string(label)))
+	}
+	labelSet, d := types.SetValue(types.StringType, labels)
+	diags.Append(d...)
+
+	return &customerResourceModel{
+		Id:          types.StringValue(query.Customer.Id.(string)),
+		Name:        types.StringValue(string(query.Customer.Name)),
+		Description: types.StringValue(string(query.Customer.Description)),
+		ExternalId:  types.StringValue(string(query.Customer.ExternalId)),
+		Labels:      labelSet,
+		AvatarUrl:   types.StringValue(string(query.Customer.AvatarUrl)),
+	}
+}
+
+type CreateCustomerInput struct {
+	Name        graphql.String   `json:"name"`
+	Description graphql.String   `json:"description,omitempty"`
+	ExternalId  graphql.String   `json:"externalId,omitempty"`
+	Labels      []graphql.String `json:"labels,omitempty"`
+	AvatarUrl   graphql.String   `json:"avatarUrl,omitempty"`
+}
+
+// UpdateCustomerInput is the updateCustomer mutation input. Description/ExternalId
+// are non-omitempty pointers: a "" pointer clears them, nil leaves them untouched.
+// Labels is sent only when it changed, and then as the complete replacement list.
+type UpdateCustomerInput struct {
+	Id          graphql.ID        `json:"id"`
+	Name        graphql.String    `json:"name,omitempty"`
+	Description *graphql.String   `json:"description"`
+	ExternalId  *graphql.String   `json:"externalId"`
+	Labels      *[]graphql.String `json:"labels,omitempty"`
+	AvatarUrl   graphql.String    `json:"avatarUrl,omitempty"`
+}
+
+type DeleteCustomerInput struct {
+	Id graphql.ID `json:"id"`
+}
+
+// buildUpdateCustomerInput includes only changed fields, mirroring
+// buildUpdateUserInput: unknown plan values are never sent.
+func buildUpdateCustomerInput(ctx context.Context, plan, state customerResourceModel, diags *diag.Diagnostics) UpdateCustomerInput {
+	input := UpdateCustomerInput{
+		Id: graphql.ID(state.Id.ValueString()),
+	}
+
+	if !plan.Name.Equal(state.Name) && !plan.Name.IsUnknown() {
+		input.Name = graphql.String(plan.Name.ValueString())
+	}
+	if !plan.Description.Equal(state.Description) && !plan.Description.IsUnknown() && !plan.Description.IsNull() {
+		description := graphql.String(plan.Description.ValueString())
+		input.Description = &description
+	}
+	if !plan.ExternalId.Equal(state.ExternalId) && !plan.ExternalId.IsUnknown() && !plan.ExternalId.IsNull() {
+		externalId := graphql.String(plan.ExternalId.ValueString())
+		input.ExternalId = &externalId
+	}
+	if !plan.Labels.Equal(state.Labels) && !plan.Labels.IsUnknown() {
+		labels := labelsFromSet(ctx, plan.Labels, diags)
+		if labels == nil {
+			labels = []graphql.String{}
+		}
+		input.Labels = &labels
+	}
+	if !plan.AvatarUrl.Equal(state.AvatarUrl) && !plan.AvatarUrl.IsUnknown() {
+		input.AvatarUrl = graphql.String(plan.AvatarUrl.ValueString())
+	}
+
+	return input
+}
+
+// labelsFromSet converts a set of label strings into the list form the API expects.
+// A null or unknown set yields nil.
+func labelsFromSet(ctx context.Context, set types.Set, diags *diag.Diagnostics) []graphql.String {
+	if set.IsNull() || set.IsUnknown() {
+		return nil
+	}
+	var values []string
+	diags.Append(set.ElementsAs(ctx, &values, false)...)
+
+	labels := make([]graphql.String, 0, len(values))
+	for _, v := range values {
+		labels = append(labels, graphql.String(v))
+	}
+	return labels
+}
+
+func (r *customerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan customerResourceModel
+	var state customerResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mutation struct {
+		UpdateCustomer struct {
+			Customer struct {
+				Id graphql.ID
+			}
+			Errors util.GqlErrors
+		} `graphql:"updateCustomer(input: $input)"`
+	}
+
+	input := buildUpdateCustomerInput(ctx, plan, state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	variables := map[string]interface{}{
+		"input": input,
+	}
+
+	if err := r.client.Mutate(ctx, &mutation, variables); err != nil {
+		resp.Diagnostics.AddError("Unable to update customer", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(gqlErrorDiagnostics(mutation.UpdateCustomer.Errors)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	newState := r.read(ctx, state.Id.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if newState == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	newState.Timeouts = plan.Timeouts
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+func (r *customerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state customerResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mutation struct {
+		DeleteCustomer struct {
+			Customer struct {
+				Id graphql.ID
+			}
+			Errors util.GqlErrors
+		} `graphql:"deleteCustomer(input: $input)"`
+	}
+
+	variables := map[string]interface{}{
+		"input": DeleteCustomerInput{
+			Id: graphql.ID(state.Id.ValueString()),
+		},
+	}
+
+	if err := r.client.Mutate(ctx, &mutation, variables); err != nil {
+		resp.Diagnostics.AddError("Unable to delete customer", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(gqlErrorDiagnostics(mutation.DeleteCustomer.Errors)...)
+}
+
+// ImportState accepts either a Prismatic customer ID or `external_id:<value>`, which
+// is resolved to the ID of the single customer carrying that external ID.
+func (r *customerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	externalId, ok := strings.CutPrefix(req.ID, customerExternalIdImportPrefix)
+	if !ok {
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+		return
+	}
+
+	var query struct {
+		Customers struct {
+			Nodes []struct {
+				Id graphql.ID
+			}
+		} `graphql:"customers(externalId: $externalId)"`
+	}
+	variables := map[string]interface{}{
+		"externalId": graphql.String(externalId),
+	}
+
+	if err := r.client.Query(ctx, &query, variables); err != nil {
+		resp.Diagnostics.AddError("Unable to import customer", err.Error())
+		return
+	}
+
+	switch len(query.Customers.Nodes) {
+	case 0:
+		resp.Diagnostics.AddError("Unable to import customer", fmt.Sprintf("No customer found with external ID %q.", externalId))
+	case 1:
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), query.Customers.Nodes[0].Id.(string))...)
+	default:
+		resp.Diagnostics.AddError("Unable to import customer", fmt.Sprintf("Found %d customers with external ID %q; import by ID instead.", len(query.Customers.Nodes), externalId))
+	}
+}
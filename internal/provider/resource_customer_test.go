@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/shurcooL/graphql"
+)
+
+const (
+	customerResourceName    = "prismatic_customer.test"
+	testCustomerName        = "Terraform Test Customer"
+	testCustomerUpdatedName = "Terraform Test Customer Updated"
+	testCustomerExternalId  = "terraform-test-customer"
+	testCustomerDescription = "Customer managed by the Terraform acceptance tests"
+)
+
+func customerConfig(name string, labels ...string) string {
+	quoted := make([]string, 0, len(labels))
+	for _, l := range labels {
+		quoted = append(quoted, fmt.Sprintf("%q", l))
+	}
+	return fmt.Sprintf(`
+resource "prismatic_customer" "test" {
+  name        = %q
+  description = %q
+  external_id = %q
+  labels      = [%s]
+}
+`, name, testCustomerDescription, testCustomerExternalId, strings.Join(quoted, ", "))
+}
+
+func TestAccResourceCustomer_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCustomerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: customerConfig(testCustomerName, "terraform"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(customerResourceName, "id"),
+					resource.TestCheckResourceAttr(customerResourceName, "name", testCustomerName),
+					resource.TestCheckResourceAttr(customerResourceName, "description", testCustomerDescription),
+					resource.TestCheckResourceAttr(customerResourceName, "external_id", testCustomerExternalId),
+					resource.TestCheckResourceAttr(customerResourceName, "labels.#", "1"),
+				),
+			},
+			{
+				Config: customerConfig(testCustomerUpdatedName, "terraform", "acceptance"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(customerResourceName, "name", testCustomerUpdatedName),
+					resource.TestCheckResourceAttr(customerResourceName, "labels.#", "2"),
+				),
+			},
+			// Import by Prismatic ID
+			{
+				ResourceName:      customerResourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Import by external ID
+			{
+				ResourceName:      customerResourceName,
+				ImportState:       true,
+				ImportStateId:     customerExternalIdImportPrefix + testCustomerExternalId,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCustomerDestroy(s *terraform.State) error {
+	client, err := testAccGraphQLClient()
+	if err != nil {
+		return err
+	}
+
+	var query struct {
+		Customers struct {
+			TotalCount int
+		} `graphql:"customers(externalId: $externalId)"`
+	}
+	variables := map[string]interface{}{
+		"externalId": graphql.String(testCustomerExternalId),
+	}
+
+	if err := client.Query(context.Background(), &query, variables); err != nil {
+		return err
+	}
+
+	if query.Customers.TotalCount != 0 {
+		return errors.New("found customer that should have been deleted")
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func labelSet(labels ...string) types.Set {
+	values := make([]attr.Value, 0, len(labels))
+	for _, l := range labels {
+		values = append(values, types.StringValue(l))
+	}
+	return types.SetValueMust(types.StringType, values)
+}
+
+// TestBuildUpdateCustomerInput checks that only changed, known fields are sent, and
+// that labels are sent as a complete list (including an empty one to clear them).
+func TestBuildUpdateCustomerInput(t *testing.T) {
+	state := customerResourceModel{
+		Id:          types.StringValue("customer-123"),
+		Name:        types.StringValue("Acme"),
+		Description: types.StringValue("A customer"),
+		ExternalId:  types.StringValue("acme"),
+		Labels:      labelSet("a", "b"),
+		AvatarUrl:   types.StringValue(""),
+	}
+
+	t.Run("unchanged values are not sent", func(t *testing.T) {
+		var diags diag.Diagnostics
+		input := buildUpdateCustomerInput(context.Background(), state, state, &diags)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %+v", diags)
+		}
+		if input.Name != "" || input.Description != nil || input.ExternalId != nil || input.Labels != nil || input.AvatarUrl != "" {
+			t.Errorf("expected only the id to be set, got %+v", input)
+		}
+	})
+
+	t.Run("changed values are sent", func(t *testing.T) {
+		plan := state
+		plan.Name = types.StringValue("Acme Corp")
+		plan.ExternalId = types.StringValue("")
+		plan.Labels = labelSet("c")
+
+		var diags diag.Diagnostics
+		input := buildUpdateCustomerInput(context.Background(), plan, state, &diags)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %+v", diags)
+		}
+		if input.Name != "Acme Corp" {
+			t.Errorf("Name = %q, want %q", input.Name, "Acme Corp")
+		}
+		if input.Description != nil {
+			t.Errorf("Description = %v, want nil", *input.Description)
+		}
+		if input.ExternalId == nil || *input.ExternalId != "" {
+			t.Errorf("ExternalId = %v, want explicit clear", input.ExternalId)
+		}
+		if input.Labels == nil || len(*input.Labels) != 1 || (*input.Labels)[0] != "c" {
+			t.Errorf("Labels = %v, want [c]", input.Labels)
+		}
+	})
+
+	t.Run("emptied labels are sent as an empty list", func(t *testing.T) {
+		plan := state
+		plan.Labels = labelSet()
+
+		var diags diag.Diagnostics
+		input := buildUpdateCustomerInput(context.Background(), plan, state, &diags)
+		if input.Labels == nil || len(*input.Labels) != 0 {
+			t.Errorf("Labels = %v, want empty list", input.Labels)
+		}
+	})
+
+	t.Run("unknown values are not sent", func(t *testing.T) {
+		plan := state
+		plan.Name = types.StringUnknown()
+		plan.Labels = types.SetUnknown(types.StringType)
+		plan.AvatarUrl = types.StringUnknown()
+
+		var diags diag.Diagnostics
+		input := buildUpdateCustomerInput(context.Background(), plan, state, &diags)
+		if input.Name != "" || input.Labels != nil || input.AvatarUrl != "" {
+			t.Errorf("expected unknown values to be omitted, got %+v", input)
+		}
+	})
+}
+
+func TestUnitResourceCustomer_lifecycle(t *testing.T) {
+	testUnitPreCheck(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCustomerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: customerConfig(testCustomerName, "terraform"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(customerResourceName, "id"),
+					resource.TestCheckResourceAttr(customerResourceName, "name", testCustomerName),
+					resource.TestCheckResourceAttr(customerResourceName, "labels.#", "1"),
+				),
+			},
+			{
+				Config: customerConfig(testCustomerUpdatedName, "terraform", "acceptance"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(customerResourceName, "name", testCustomerUpdatedName),
+					resource.TestCheckResourceAttr(customerResourceName, "labels.#", "2"),
+				),
+			},
+			{
+				ResourceName:      customerResourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      customerResourceName,
+				ImportState:       true,
+				ImportStateId:     customerExternalIdImportPrefix + testCustomerExternalId,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,611 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/prismatic-io/terraform-provider-prismatic/internal/util"
+	"github.com/shurcooL/graphql"
+)
+
+var (
+	_ resource.Resource                = (*instanceResource)(nil)
+	_ resource.ResourceWithConfigure   = (*instanceResource)(nil)
+	_ resource.ResourceWithImportState = (*instanceResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*instanceResource)(nil)
+)
+
+type instanceResource struct {
+	client *graphql.Client
+}
+
+type instanceResourceModel struct {
+	Id                   types.String   `tfsdk:"id"`
+	Name                 types.String   `tfsdk:"name"`
+	Description          types.String   `tfsdk:"description"`
+	IntegrationId        types.String   `tfsdk:"integration_id"`
+	CustomerId           types.String   `tfsdk:"customer_id"`
+	Version              types.Int64    `tfsdk:"version"`
+	ConfigVariables      types.Map      `tfsdk:"config_variables"`
+	IntegrationVersionId types.String   `tfsdk:"integration_version_id"`
+	DeployedVersion      types.Int64    `tfsdk:"deployed_version"`
+	LastDeployedAt       types.String   `tfsdk:"last_deployed_at"`
+	Timeouts             timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *instanceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instance"
+}
+
+func (r *instanceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Deploy an Instance of an Integration to a Customer in Prismatic.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the Instance.",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the Instance.",
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "The description of the Instance.",
+			},
+			"integration_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the Integration to deploy. Changing this will recreate the Instance.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"customer_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the Customer to deploy the Instance to. Changing this will recreate the Instance.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The published Integration version number to deploy. Reference the `version_number` of a `prismatic_integration_version` resource to deploy a version published in the same apply. When omitted, the Instance tracks the latest published version and is redeployed on the next apply after a newer version is published.",
+			},
+			"config_variables": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Config variable values for the Instance, keyed by config variable key. Config variables not listed here keep their current or default values.",
+			},
+			"integration_version_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the Integration version the Instance is deployed with.",
+			},
+			"deployed_version": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The Integration version number the Instance is deployed with.",
+			},
+			"last_deployed_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "The timestamp when the Instance was last deployed.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *instanceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+// ModifyPlan resolves the Integration version the Instance should run. When the
+// resolved version differs from the deployed one, the deployment attributes are
+// marked unknown so Terraform plans a redeploy; otherwise they keep their state,
+// and last_deployed_at only changes if the config variables do.
+func (r *instanceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan, state instanceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.IntegrationId.IsUnknown() || plan.Version.IsUnknown() {
+		return
+	}
+
+	versionId, versionNumber, err := resolveIntegrationVersion(ctx, r.client, plan.IntegrationId.ValueString(), plan.Version)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("version"), "Unable to resolve integration version", err.Error())
+		return
+	}
+
+	if versionId == state.IntegrationVersionId.ValueString() {
+		plan.IntegrationVersionId = state.IntegrationVersionId
+		plan.DeployedVersion = state.DeployedVersion
+		if plan.ConfigVariables.Equal(state.ConfigVariables) {
+			plan.LastDeployedAt = state.LastDeployedAt
+		}
+	} else {
+		plan.IntegrationVersionId = types.StringUnknown()
+		plan.DeployedVersion = types.Int64Value(versionNumber)
+		plan.LastDeployedAt = types.StringUnknown()
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *instanceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan instanceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	versionId, versionNumber, err := resolveIntegrationVersion(ctx, r.client, plan.IntegrationId.ValueString(), plan.Version)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create instance", err.Error())
+		return
+	}
+
+	configVariables := instanceConfigVariables(ctx, plan.ConfigVariables, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mutation struct {
+		CreateInstance struct {
+			Instance struct {
+				Id graphql.ID
+			}
+			Errors util.GqlErrors
+		} `graphql:"createInstance(input: $input)"`
+	}
+	variables := map[string]interface{}{
+		"input": CreateInstanceInput{
+			Customer:        graphql.ID(plan.CustomerId.ValueString()),
+			Integration:     graphql.ID(versionId),
+			Name:            graphql.String(plan.Name.ValueString()),
+			Description:     graphql.String(plan.Description.ValueString()),
+			ConfigVariables: configVariables,
+		},
+	}
+
+	if err := r.client.Mutate(ctx, &mutation, variables); err != nil {
+		resp.Diagnostics.AddError("Unable to create instance", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(gqlErrorDiagnostics(mutation.CreateInstance.Errors)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := mutation.CreateInstance.Instance.Id.(string)
+
+	// The instance exists from here on, so record it before deploying: a failed
+	// deploy then leaves a tainted resource rather than an untracked instance.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+
+	r.deploy(ctx, id, versionNumber, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := r.read(ctx, id, plan.ConfigVariables, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state == nil {
+		resp.Diagnostics.AddError("Unable to read instance", "Instance was deployed but could not be found.")
+		return
+	}
+	state.copyDeploymentInputsFrom(plan)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *instanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state instanceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	newState := r.read(ctx, state.Id.ValueString(), state.ConfigVariables, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if newState == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	newState.copyDeploymentInputsFrom(state)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+func (r *instanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan instanceResourceModel
+	var state instanceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	versionId, versionNumber, err := resolveIntegrationVersion(ctx, r.client, plan.IntegrationId.ValueString(), plan.Version)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to update instance", err.Error())
+		return
+	}
+
+	configVariables := instanceConfigVariables(ctx, plan.ConfigVariables, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mutation struct {
+		UpdateInstance struct {
+			Instance struct {
+				Id graphql.ID
+			}
+			Errors util.GqlErrors
+		} `graphql:"updateInstance(input: $input)"`
+	}
+	variables := map[string]interface{}{
+		"input": UpdateInstanceInput{
+			Id:              graphql.ID(state.Id.ValueString()),
+			Integration:     graphql.ID(versionId),
+			Name:            graphql.String(plan.Name.ValueString()),
+			Description:     graphql.String(plan.Description.ValueString()),
+			ConfigVariables: configVariables,
+		},
+	}
+
+	if err := r.client.Mutate(ctx, &mutation, variables); err != nil {
+		resp.Diagnostics.AddError("Unable to update instance", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(gqlErrorDiagnostics(mutation.UpdateInstance.Errors)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A rename or a timeouts change must not redeploy to the Customer.
+	if versionId != state.IntegrationVersionId.ValueString() || !plan.ConfigVariables.Equal(state.ConfigVariables) {
+		r.deploy(ctx, state.Id.ValueString(), versionNumber, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	newState := r.read(ctx, state.Id.ValueString(), plan.ConfigVariables, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if newState == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	newState.copyDeploymentInputsFrom(plan)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
+}
+
+func (r *instanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state instanceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mutation struct {
+		DeleteInstance struct {
+			Instance struct {
+				Id graphql.ID
+			}
+			Errors util.GqlErrors
+		} `graphql:"deleteInstance(input: $input)"`
+	}
+	variables := map[string]interface{}{
+		"input": DeleteInstanceInput{
+			Id: graphql.ID(state.Id.ValueString()),
+		},
+	}
+
+	if err := r.client.Mutate(ctx, &mutation, variables); err != nil {
+		resp.Diagnostics.AddError("Unable to delete instance", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(gqlErrorDiagnostics(mutation.DeleteInstance.Errors)...)
+}
+
+func (r *instanceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// read queries the Instance by id and maps it to a model, returning nil if the
+// Instance no longer exists. An Instance carries every config variable of its
+// Integration, so only the keys managed in configVariables are mapped back; a null
+// configVariables (e.g. on import) leaves the attribute null. The deployment inputs
+// are restored by the caller with copyDeploymentInputsFrom.
+func (r *instanceResource) read(ctx context.Context, id string, configVariables types.Map, diags *diag.Diagnostics) *instanceResourceModel {
+	var query struct {
+		Instance struct {
+			Id              graphql.ID
+			Name            graphql.String
+			Description     graphql.String
+			LastDeployedAt  graphql.String
+			DeployedVersion graphql.Int
+			Integration     struct {
+				Id              graphql.ID
+				VersionSequence struct {
+					Nodes []struct {
+						Id            graphql.ID
+						VersionNumber graphql.Int
+					}
+				}
+			}
+			Customer struct {
+				Id graphql.ID
+			}
+			ConfigVariables struct {
+				Nodes []struct {
+					Value                  graphql.String
+					RequiredConfigVariable struct {
+						Key graphql.String
+					}
+				}
+			}
+		} `graphql:"instance(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id": graphql.ID(id),
+	}
+	if err := r.client.Query(ctx, &query, variables); err != nil {
+		if isRecordNotFound(err) {
+			return nil
+		}
+		diags.AddError("Unable to read instance", err.Error())
+		return nil
+	}
+
+	managed := types.MapNull(types.StringType)
+	if !configVariables.IsNull() && !configVariables.IsUnknown() {
+		values := make(map[string]string, len(configVariables.Elements()))
+		for _, node := range query.Instance.ConfigVariables.Nodes {
+			key := string(node.RequiredConfigVariable.Key)
+			if _, ok := configVariables.Elements()[key]; ok {
+				values[key] = string(node.Value)
+			}
+		}
+		m, d := types.MapValueFrom(ctx, types.StringType, values)
+		diags.Append(d...)
+		managed = m
+	}
+
+	// The Instance reports the deployed version; the Integration itself is the
+	// draft at the head of that version's sequence, numbered 0.
+	integrationId := query.Instance.Integration.Id.(string)
+	for _, version := range query.Instance.Integration.VersionSequence.Nodes {
+		if version.VersionNumber == 0 {
+			integrationId = version.Id.(string)
+			break
+		}
+	}
+
+	return &instanceResourceModel{
+		Id:                   types.StringValue(query.Instance.Id.(string)),
+		Name:                 types.StringValue(string(query.Instance.Name)),
+		Description:          types.StringValue(string(query.Instance.Description)),
+		IntegrationId:        types.StringValue(integrationId),
+		CustomerId:           types.StringValue(query.Instance.Customer.Id.(string)),
+		ConfigVariables:      managed,
+		IntegrationVersionId: types.StringValue(query.Instance.Integration.Id.(string)),
+		DeployedVersion:      types.Int64Value(int64(query.Instance.DeployedVersion)),
+		LastDeployedAt:       types.StringValue(string(query.Instance.LastDeployedAt)),
+	}
+}
+
+// copyDeploymentInputsFrom carries the configuration-only deployment inputs over
+// from the plan or prior state into a freshly read model.
+func (m *instanceResourceModel) copyDeploymentInputsFrom(src instanceResourceModel) {
+	m.Version = src.Version
+	m.Timeouts = src.Timeouts
+}
+
+// resolveIntegrationVersion finds the published version of an Integration to deploy:
+// the given version number when set, otherwise the latest published version. It
+// returns the version's own id along with its version number.
+func resolveIntegrationVersion(ctx context.Context, client *graphql.Client, integrationId string, version types.Int64) (string, int64, error) {
+	var query struct {
+		Integration struct {
+			VersionSequence struct {
+				Nodes []struct {
+					Id                 graphql.ID
+					VersionNumber      graphql.Int
+					VersionIsAvailable graphql.Boolean
+				}
+			}
+		} `graphql:"integration(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id": graphql.ID(integrationId),
+	}
+	if err := client.Query(ctx, &query, variables); err != nil {
+		return "", 0, err
+	}
+
+	var latestId string
+	var latestNumber int64
+	for _, node := range query.Integration.VersionSequence.Nodes {
+		number := int64(node.VersionNumber)
+		if !bool(node.VersionIsAvailable) {
+			continue
+		}
+		if !version.IsNull() {
+			if number == version.ValueInt64() {
+				return node.Id.(string), number, nil
+			}
+			continue
+		}
+		if number > latestNumber {
+			latestId, latestNumber = node.Id.(string), number
+		}
+	}
+
+	if !version.IsNull() {
+		return "", 0, fmt.Errorf("integration %q has no published version %d", integrationId, version.ValueInt64())
+	}
+	if latestId == "" {
+		return "", 0, fmt.Errorf("integration %q has no published versions; publish a version before deploying an instance", integrationId)
+	}
+	return latestId, latestNumber, nil
+}
+
+// instanceConfigVariables converts the config_variables map into mutation input,
+// sorted by key so the request is stable.
+func instanceConfigVariables(ctx context.Context, m types.Map, diags *diag.Diagnostics) []InputInstanceConfigVariable {
+	if m.IsNull() || m.IsUnknown() {
+		return nil
+	}
+	var values map[string]string
+	diags.Append(m.ElementsAs(ctx, &values, false)...)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	variables := make([]InputInstanceConfigVariable, 0, len(keys))
+	for _, k := range keys {
+		variables = append(variables, InputInstanceConfigVariable{
+			Key:   graphql.String(k),
+			Value: graphql.String(values[k]),
+		})
+	}
+	return variables
+}
+
+// deploy runs the deployInstance mutation and waits for the deployment to land on
+// the expected Integration version, recording diagnostics on failure.
+func (r *instanceResource) deploy(ctx context.Context, id string, versionNumber int64, diags *diag.Diagnostics) {
+	var mutation struct {
+		DeployInstance struct {
+			Instance struct {
+				Id graphql.ID
+			}
+			Errors util.GqlErrors
+		} `graphql:"deployInstance(input: $input)"`
+	}
+	variables := map[string]interface{}{
+		"input": DeployInstanceInput{
+			Id: graphql.ID(id),
+		},
+	}
+	if err := r.client.Mutate(ctx, &mutation, variables); err != nil {
+		diags.AddError("Unable to deploy instance", err.Error())
+		return
+	}
+
+	diags.Append(gqlErrorDiagnostics(mutation.DeployInstance.Errors)...)
+	if diags.HasError() {
+		return
+	}
+
+	if err := waitForInstanceDeployment(ctx, r.client, id, versionNumber); err != nil {
+		diags.AddError("Unable to deploy instance", err.Error())
+	}
+}
+
+// waitForInstanceDeployment polls until the Instance reports that it is deployed
+// with the given Integration version number and has no pending deploy, for as long
+// as ctx allows.
+func waitForInstanceDeployment(ctx context.Context, client *graphql.Client, id string, versionNumber int64) error {
+	for {
+		var query struct {
+			Instance struct {
+				DeployedVersion graphql.Int
+				NeedsDeploy     graphql.Boolean
+			} `graphql:"instance(id: $id)"`
+		}
+		variables := map[string]interface{}{"id": graphql.ID(id)}
+		if err := client.Query(ctx, &query, variables); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("instance %q not yet deployed with version %d: %w", id, versionNumber, ctx.Err())
+			}
+			return err
+		}
+		if int64(query.Instance.DeployedVersion) == versionNumber && !bool(query.Instance.NeedsDeploy) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("instance %q not yet deployed with version %d: %w", id, versionNumber, ctx.Err())
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+type InputInstanceConfigVariable struct {
+	Key   graphql.String `json:"key"`
+	Value graphql.String `json:"value"`
+}
+
+type CreateInstanceInput struct {
+	Customer        graphql.ID                    `json:"customer"`
+	Integration     graphql.ID                    `json:"integration"`
+	Name            graphql.String                `json:"name"`
+	Description     graphql.String                `json:"description"`
+	ConfigVariables []InputInstanceConfigVariable `json:"configVariables,omitempty"`
+}
+
+type UpdateInstanceInput struct {
+	Id              graphql.ID                    `json:"id"`
+	Integration     graphql.ID                    `json:"integration"`
+	Name            graphql.String                `json:"name"`
+	Description     graphql.String                `json:"description"`
+	ConfigVariables []InputInstanceConfigVariable `json:"configVariables,omitempty"`
+}
+
+type DeployInstanceInput struct {
+	Id graphql.ID `json:"id"`
+}
+
+type DeleteInstanceInput struct {
+	Id graphql.ID `json:"id"`
+}
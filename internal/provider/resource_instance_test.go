@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/shurcooL/graphql"
+)
+
+const instanceResourceName = "prismatic_instance.instance"
+
+func instanceConfig(definition, instanceName string) string {
+	return fmt.Sprintf(`
+resource "prismatic_customer" "customer" {
+  name        = "Terraform Instance Test Customer"
+  external_id = "terraform-instance-test-customer"
+}
+
+resource "prismatic_integration" "integration" {
+  definition = <<EOF
+%s
+EOF
+}
+
+resource "prismatic_integration_version" "version" {
+  integration_id = prismatic_integration.integration.id
+  definition     = prismatic_integration.integration.definition
+}
+
+resource "prismatic_instance" "instance" {
+  name           = %q
+  customer_id    = prismatic_customer.customer.id
+  integration_id = prismatic_integration_version.version.integration_id
+  version        = prismatic_integration_version.version.version_number
+}`, definition, instanceName)
+}
+
+func TestAccResourceInstance_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccCheckInstanceDestroy,
+			testAccCheckIntegrationResourceDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: instanceConfig(baseDefinition, "Terraform Test Instance"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(instanceResourceName, "id"),
+					resource.TestCheckResourceAttr(instanceResourceName, "name", "Terraform Test Instance"),
+					resource.TestCheckResourceAttrPair(instanceResourceName, "integration_id", resourceName, "id"),
+					resource.TestCheckResourceAttrPair(instanceResourceName, "integration_version_id", integrationVersionResourceName, "id"),
+					resource.TestCheckResourceAttrPair(instanceResourceName, "deployed_version", integrationVersionResourceName, "version_number"),
+					resource.TestCheckResourceAttrSet(instanceResourceName, "last_deployed_at"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				ResourceName:            instanceResourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"version"},
+			},
+			{
+				Config: instanceConfig(baseDefinition, "Terraform Test Instance Renamed"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(instanceResourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(instanceResourceName, "name", "Terraform Test Instance Renamed"),
+				),
+			},
+			// Publishing a new version redeploys an Instance pinned to it.
+			{
+				Config: instanceConfig(updateDefinition, "Terraform Test Instance Renamed"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(instanceResourceName, "integration_version_id", integrationVersionResourceName, "id"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckInstanceDestroy checks that the Instances in the state are gone.
+func testAccCheckInstanceDestroy(s *terraform.State) error {
+	client, err := testAccGraphQLClient()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "prismatic_instance" {
+			continue
+		}
+
+		var query struct {
+			Instance struct {
+				Id graphql.ID
+			} `graphql:"instance(id: $id)"`
+		}
+		variables := map[string]interface{}{
+			"id": graphql.ID(rs.Primary.ID),
+		}
+		err := client.Query(context.Background(), &query, variables)
+		if err == nil {
+			return fmt.Errorf("found instance %s that should have been deleted", rs.Primary.ID)
+		}
+		if !isRecordNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/compare"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestInstanceConfigVariables(t *testing.T) {
+	m := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"zeta":  types.StringValue("z"),
+		"alpha": types.StringValue("a"),
+	})
+
+	var diags diag.Diagnostics
+	got := instanceConfigVariables(context.Background(), m, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %+v", diags)
+	}
+	if len(got) != 2 || got[0].Key != "alpha" || got[0].Value != "a" || got[1].Key != "zeta" || got[1].Value != "z" {
+		t.Errorf("instanceConfigVariables = %+v, want alpha then zeta", got)
+	}
+
+	if got := instanceConfigVariables(context.Background(), types.MapNull(types.StringType), &diags); got != nil {
+		t.Errorf("instanceConfigVariables(null) = %+v, want nil", got)
+	}
+}
+
+// TestCopyDeploymentInputsFrom checks that the configuration-only inputs are
+// restored while the read integration_id is left alone.
+func TestCopyDeploymentInputsFrom(t *testing.T) {
+	read := instanceResourceModel{IntegrationId: types.StringValue("integration-id")}
+
+	kept := read
+	kept.copyDeploymentInputsFrom(instanceResourceModel{
+		IntegrationId: types.StringValue("other-id"),
+		Version:       types.Int64Value(3),
+	})
+	if kept.IntegrationId.ValueString() != "integration-id" || kept.Version.ValueInt64() != 3 {
+		t.Errorf("inputs were not restored over the read values: %+v", kept)
+	}
+
+	imported := read
+	imported.copyDeploymentInputsFrom(instanceResourceModel{
+		IntegrationId: types.StringNull(),
+		Version:       types.Int64Null(),
+	})
+	if imported.IntegrationId.ValueString() != "integration-id" || !imported.Version.IsNull() {
+		t.Errorf("import did not keep the read values: %+v", imported)
+	}
+}
+
+func TestUnitResourceInstance_lifecycle(t *testing.T) {
+	testUnitPreCheck(t)
+
+	sameDeployment := statecheck.CompareValue(compare.ValuesSame())
+	lastDeployedAt := tfjsonpath.New("last_deployed_at")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccCheckInstanceDestroy,
+			testAccCheckIntegrationResourceDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: instanceConfig(baseDefinition, "Terraform Test Instance"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(instanceResourceName, "id"),
+					resource.TestCheckResourceAttrPair(instanceResourceName, "integration_id", resourceName, "id"),
+					resource.TestCheckResourceAttrPair(instanceResourceName, "integration_version_id", integrationVersionResourceName, "id"),
+					resource.TestCheckResourceAttr(instanceResourceName, "deployed_version", "1"),
+					resource.TestCheckResourceAttrSet(instanceResourceName, "last_deployed_at"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					sameDeployment.AddStateValue(instanceResourceName, lastDeployedAt),
+				},
+			},
+			// Import reads the Integration's own id, not the deployed version's.
+			{
+				ResourceName:            instanceResourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"version"},
+			},
+			// A rename updates the Instance without redeploying it.
+			{
+				Config: instanceConfig(baseDefinition, "Terraform Test Instance Renamed"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(instanceResourceName, plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue(instanceResourceName, lastDeployedAt, knownvalue.NotNull()),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					sameDeployment.AddStateValue(instanceResourceName, lastDeployedAt),
+				},
+				Check: resource.TestCheckResourceAttr(instanceResourceName, "name", "Terraform Test Instance Renamed"),
+			},
+			{
+				Config: instanceConfig(updateDefinition, "Terraform Test Instance Renamed"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(instanceResourceName, "integration_version_id", integrationVersionResourceName, "id"),
+					resource.TestCheckResourceAttr(instanceResourceName, "deployed_version", "2"),
+				),
+			},
+		},
+	})
+}
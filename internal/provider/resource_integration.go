@@ -3,7 +3,11 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -16,9 +20,11 @@ import (
 )
 
 var (
-	_ resource.Resource                = (*integrationResource)(nil)
-	_ resource.ResourceWithConfigure   = (*integrationResource)(nil)
-	_ resource.ResourceWithImportState = (*integrationResource)(nil)
+	_ resource.Resource                   = (*integrationResource)(nil)
+	_ resource.ResourceWithConfigure      = (*integrationResource)(nil)
+	_ resource.ResourceWithImportState    = (*integrationResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*integrationResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*integrationResource)(nil)
 )
 
 type integrationResource struct {
@@ -26,10 +32,15 @@ type integrationResource struct {
 }
 
 type integrationResourceModel struct {
-	Id          types.String          `tfsdk:"id"`
-	Definition  definitionStringValue `tfsdk:"definition"`
-	Name        types.String          `tfsdk:"name"`
-	Description types.String          `tfsdk:"description"`
+	Id                  types.String          `tfsdk:"id"`
+	Definition          definitionStringValue `tfsdk:"definition"`
+	DefinitionFiles     types.List            `tfsdk:"definition_files"`
+	DefinitionDirectory types.String          `tfsdk:"definition_directory"`
+	DefinitionHash      types.String          `tfsdk:"definition_hash"`
+	ComponentVersions   types.Map             `tfsdk:"component_versions"`
+	Name                types.String          `tfsdk:"name"`
+	Description         types.String          `tfsdk:"description"`
+	Timeouts            timeouts.Value        `tfsdk:"timeouts"`
 }
 
 type ImportIntegrationInput struct {
@@ -55,8 +66,27 @@ func (r *integrationResource) Schema(ctx context.Context, req resource.SchemaReq
 			},
 			"definition": schema.StringAttribute{
 				CustomType:  definitionStringType{},
-				Required:    true,
-				Description: "The YAML definition of the Integration",
+				Optional:    true,
+				Computed:    true,
+				Description: "The YAML definition of the Integration. Its structure (name, flows and their trigger steps, component references, config variables and pages) is validated when planning. Exactly one of definition, definition_files and definition_directory must be set; with either of the others, this is the merged definition.",
+			},
+			"definition_files": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Paths of YAML files to merge, in order, into the definition of the Integration: maps are merged key by key, lists are concatenated, and a scalar may only be repeated with the same value. Relative paths are resolved against the working directory, so prefix them with path.module.",
+			},
+			"definition_directory": schema.StringAttribute{
+				Optional:    true,
+				Description: "A directory whose .yml and .yaml files, including those in subdirectories but not hidden ones, are merged in lexical order of their paths into the definition of the Integration, as with definition_files.",
+			},
+			"definition_hash": schema.StringAttribute{
+				Computed:    true,
+				Description: "The SHA-256 of the definition merged from definition_files or definition_directory, so that an edit to any of the files shows in the plan. Null when definition is set directly.",
+			},
+			"component_versions": schema.MapAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+				Description: "Versions of private Components to use, by Component key: each reference to one of these Components in the definition has its version replaced when the Integration is imported. Set the versions from prismatic_component resources (`{ (prismatic_component.example.key) = prismatic_component.example.version_number }`) so that publishing a new version of a Component re-imports the Integration. The definition in state keeps the versions as written. Every key must be referenced by the definition.",
 			},
 			"name": schema.StringAttribute{
 				Computed:    true,
@@ -67,6 +97,14 @@ func (r *integrationResource) Schema(ctx context.Context, req resource.SchemaReq
 				Description: "The description of the Integration",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -77,11 +115,13 @@ func (r *integrationResource) Configure(ctx context.Context, req resource.Config
 func (r *integrationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan integrationResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	id := r.importIntegration(ctx, "", plan.Definition.ValueString(), &resp.Diagnostics)
+	id := r.importIntegration(ctx, "", plan.submittedDefinition(ctx, &resp.Diagnostics), &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -94,12 +134,16 @@ func (r *integrationResource) Create(ctx context.Context, req resource.CreateReq
 		resp.Diagnostics.AddError("Unable to read integration after create", "The integration could not be found after import.")
 		return
 	}
+	state.keepConfiguration(ctx, plan, &resp.Diagnostics)
+	state.Timeouts = plan.Timeouts
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
 func (r *integrationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state integrationResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -112,6 +156,8 @@ func (r *integrationResource) Read(ctx context.Context, req resource.ReadRequest
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	updated.keepConfiguration(ctx, state, &resp.Diagnostics)
+	updated.Timeouts = state.Timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, updated)...)
 }
@@ -119,6 +165,8 @@ func (r *integrationResource) Read(ctx context.Context, req resource.ReadRequest
 func (r *integrationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan integrationResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -130,7 +178,21 @@ func (r *integrationResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 	id := priorState.Id.ValueString()
 
-	r.importIntegration(ctx, id, plan.Definition.ValueString(), &resp.Diagnostics)
+	// Importing always creates a new draft, so skip it when only settings the API
+	// does not see (such as timeouts) changed.
+	submitted := plan.submittedDefinition(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if submitted == priorState.submittedDefinition(ctx, &resp.Diagnostics) {
+		state := priorState
+		state.keepConfiguration(ctx, plan, &resp.Diagnostics)
+		state.Timeouts = plan.Timeouts
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
+
+	r.importIntegration(ctx, id, submitted, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -143,12 +205,16 @@ func (r *integrationResource) Update(ctx context.Context, req resource.UpdateReq
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	state.keepConfiguration(ctx, plan, &resp.Diagnostics)
+	state.Timeouts = plan.Timeouts
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
 func (r *integrationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state integrationResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -175,6 +241,152 @@ func (r *integrationResource) Delete(ctx context.Context, req resource.DeleteReq
 	resp.Diagnostics.Append(gqlErrorDiagnostics(mutation.DeleteIntegration.Errors)...)
 }
 
+// ValidateConfig checks that the definition comes from exactly one source and
+// checks it against the embedded definition schema, so structural mistakes are
+// reported at plan time with the line they are on rather than by
+// importIntegration partway through an apply. Every component_versions key must
+// be referenced by the definition.
+func (r *integrationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config integrationResourceModel
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("definition"), &config.Definition)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("definition_files"), &config.DefinitionFiles)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("definition_directory"), &config.DefinitionDirectory)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sources := 0
+	for _, source := range []attr.Value{config.Definition, config.DefinitionFiles, config.DefinitionDirectory} {
+		if !source.IsNull() {
+			sources++
+		}
+	}
+	if sources != 1 {
+		resp.Diagnostics.AddError(
+			"Invalid integration definition source",
+			"Exactly one of definition, definition_files and definition_directory must be set.",
+		)
+		return
+	}
+
+	var definition string
+	if !config.Definition.IsNull() {
+		if config.Definition.IsUnknown() {
+			return
+		}
+		definition = config.Definition.ValueString()
+		for _, err := range validateIntegrationDefinition(definition) {
+			resp.Diagnostics.AddAttributeError(path.Root("definition"), "Invalid integration definition", err.Error())
+		}
+	} else {
+		attribute, files := config.readDefinitionFiles(ctx, &resp.Diagnostics)
+		if files == nil {
+			return
+		}
+		merged, errs := mergeDefinitionFiles(files)
+		if merged != nil {
+			errs = merged.validate()
+		}
+		for _, err := range errs {
+			resp.Diagnostics.AddAttributeError(attribute, "Invalid integration definition", err.Error())
+		}
+		if merged != nil {
+			definition, _ = merged.String()
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("component_versions"), &config.ComponentVersions)...)
+	if resp.Diagnostics.HasError() || config.ComponentVersions.IsNull() || config.ComponentVersions.IsUnknown() {
+		return
+	}
+	// Only the keys matter here; the versions may not be known until apply.
+	keys := map[string]int64{}
+	for key := range config.ComponentVersions.Elements() {
+		keys[key] = 0
+	}
+	_, unmatched, err := pinComponentVersions(definition, keys)
+	if err != nil {
+		return
+	}
+	for _, key := range unmatched {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("component_versions").AtMapKey(key),
+			"Unreferenced component",
+			fmt.Sprintf("The definition has no reference to a private component with key %q.", key),
+		)
+	}
+}
+
+// ModifyPlan merges a definition split across files into the planned definition
+// and its hash, and summarizes a definition change as a plan warning listing the
+// flows, steps and config variables it adds, removes or changes, since the
+// attribute diff shows only the whole YAML document replaced.
+func (r *integrationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan integrationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.DefinitionFiles.IsNull() && plan.DefinitionDirectory.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("definition_hash"), types.StringNull())...)
+	} else {
+		attribute, files := plan.readDefinitionFiles(ctx, &resp.Diagnostics)
+		if files == nil {
+			return
+		}
+		merged, errs := mergeDefinitionFiles(files)
+		for _, err := range errs {
+			resp.Diagnostics.AddAttributeError(attribute, "Invalid integration definition", err.Error())
+		}
+		if merged == nil {
+			return
+		}
+		definition, err := merged.String()
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(attribute, "Unable to merge integration definition", err.Error())
+			return
+		}
+		plan.DefinitionHash = types.StringValue(definitionHash(definition))
+		// Keep the definition in state while it is equivalent to the merged one,
+		// so edits that change nothing (comments, key order) plan only a new hash.
+		if state.Definition.IsNull() || !definitionsEquivalent(definition, state.Definition.ValueString()) {
+			plan.Definition = definitionStringValue{StringValue: basetypes.NewStringValue(definition)}
+		} else {
+			plan.Definition = state.Definition
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("definition"), plan.Definition)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("definition_hash"), plan.DefinitionHash)...)
+	}
+
+	if req.State.Raw.IsNull() || resp.Diagnostics.HasError() || plan.Definition.IsUnknown() || plan.Definition.IsNull() {
+		return
+	}
+	prior, planned := state.Definition.ValueString(), plan.Definition.ValueString()
+	if prior == planned || definitionsEquivalent(planned, prior) {
+		return
+	}
+	changes, err := definitionChanges(prior, planned)
+	if err != nil || len(changes) == 0 {
+		return
+	}
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("definition"),
+		"Integration definition changes",
+		strings.Join(changes, "\n"),
+	)
+}
+
 func (r *integrationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
@@ -233,13 +445,104 @@ func (r *integrationResource) read(ctx context.Context, id string, diags *diag.D
 	}
 
 	return &integrationResourceModel{
-		Id:          types.StringValue(query.Integration.Id.(string)),
-		Name:        types.StringValue(string(query.Integration.Name)),
-		Description: types.StringValue(string(query.Integration.Description)),
-		Definition:  definitionStringValue{StringValue: basetypes.NewStringValue(string(query.Integration.Definition))},
+		Id:                  types.StringValue(query.Integration.Id.(string)),
+		Name:                types.StringValue(string(query.Integration.Name)),
+		Description:         types.StringValue(string(query.Integration.Description)),
+		Definition:          definitionStringValue{StringValue: basetypes.NewStringValue(string(query.Integration.Definition))},
+		DefinitionFiles:     types.ListNull(types.StringType),
+		DefinitionDirectory: types.StringNull(),
+		DefinitionHash:      types.StringNull(),
+		ComponentVersions:   types.MapNull(types.Int64Type),
+	}
+}
+
+// keepConfiguration copies from from what the API does not record: the files or
+// directory the definition was merged from, their hash, and the component
+// versions. The definition read back replaces from's only if it differs from what
+// from submits, so a definition with pinned component versions keeps the versions
+// as written.
+func (m *integrationResourceModel) keepConfiguration(ctx context.Context, from integrationResourceModel, diags *diag.Diagnostics) {
+	m.DefinitionFiles = from.DefinitionFiles
+	m.DefinitionDirectory = from.DefinitionDirectory
+	m.DefinitionHash = from.DefinitionHash
+	m.ComponentVersions = from.ComponentVersions
+
+	if from.Definition.IsNull() || from.Definition.IsUnknown() {
+		return
+	}
+	if submitted := from.submittedDefinition(ctx, diags); definitionsEquivalent(submitted, m.Definition.ValueString()) {
+		m.Definition = from.Definition
 	}
 }
 
+// submittedDefinition is the definition passed to importIntegration: the
+// definition with the component versions pinned.
+func (m integrationResourceModel) submittedDefinition(ctx context.Context, diags *diag.Diagnostics) string {
+	versions := m.componentVersions(ctx, diags)
+	definition, _, err := pinComponentVersions(m.Definition.ValueString(), versions)
+	if err != nil {
+		diags.AddAttributeError(path.Root("definition"), "Unable to pin component versions", err.Error())
+		return m.Definition.ValueString()
+	}
+	return definition
+}
+
+// componentVersions returns component_versions, leaving out versions that are
+// null or not yet known. It is nil when component_versions is not set.
+func (m integrationResourceModel) componentVersions(ctx context.Context, diags *diag.Diagnostics) map[string]int64 {
+	if m.ComponentVersions.IsNull() || m.ComponentVersions.IsUnknown() {
+		return nil
+	}
+	var elements map[string]types.Int64
+	diags.Append(m.ComponentVersions.ElementsAs(ctx, &elements, false)...)
+	versions := make(map[string]int64, len(elements))
+	for key, version := range elements {
+		if !version.IsNull() && !version.IsUnknown() {
+			versions[key] = version.ValueInt64()
+		}
+	}
+	return versions
+}
+
+// readDefinitionFiles reads the files named by definition_files, or those under
+// definition_directory, returning the attribute they came from for diagnostics.
+// The files are nil if neither is set, a path is not yet known, or reading fails.
+func (m integrationResourceModel) readDefinitionFiles(ctx context.Context, diags *diag.Diagnostics) (path.Path, []definitionFile) {
+	if !m.DefinitionDirectory.IsNull() {
+		attribute := path.Root("definition_directory")
+		if m.DefinitionDirectory.IsUnknown() {
+			return attribute, nil
+		}
+		files, err := readDefinitionDirectory(m.DefinitionDirectory.ValueString())
+		if err != nil {
+			diags.AddAttributeError(attribute, "Unable to read integration definition", err.Error())
+		}
+		return attribute, files
+	}
+
+	attribute := path.Root("definition_files")
+	if m.DefinitionFiles.IsNull() || m.DefinitionFiles.IsUnknown() {
+		return attribute, nil
+	}
+	var elements []types.String
+	diags.Append(m.DefinitionFiles.ElementsAs(ctx, &elements, false)...)
+	paths := make([]string, 0, len(elements))
+	for _, element := range elements {
+		if element.IsUnknown() {
+			return attribute, nil
+		}
+		paths = append(paths, element.ValueString())
+	}
+	if diags.HasError() {
+		return attribute, nil
+	}
+	files, err := readDefinitionFiles(paths)
+	if err != nil {
+		diags.AddAttributeError(attribute, "Unable to read integration definition", err.Error())
+	}
+	return attribute, files
+}
+
 // definitionsEquivalent reports whether the submitted definition is a semantic
 // subset of the server's canonical (normalized) definition.
 func definitionsEquivalent(submitted, canonical string) bool {
@@ -309,3 +612,142 @@ func yamlEmpty(v interface{}) bool {
 		return false
 	}
 }
+
+// definitionChanges describes how the planned definition differs from the prior
+// (canonical) one, one line per added (+), removed (-) or changed (~) top-level
+// key, flow, step or config variable. Changes are judged with yamlSubset, so the
+// API's normalization of the prior definition is not reported.
+func definitionChanges(prior, planned string) ([]string, error) {
+	var p, n map[string]interface{}
+	if err := yaml.Unmarshal([]byte(prior), &p); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal([]byte(planned), &n); err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, key := range changedKeys(n, p, "flows", "steps", "requiredConfigVars", "configPages") {
+		if isYAMLScalar(n[key]) && isYAMLScalar(p[key]) {
+			changes = append(changes, fmt.Sprintf("~ %s: %q -> %q", key, fmt.Sprint(p[key]), fmt.Sprint(n[key])))
+		} else {
+			changes = append(changes, "~ "+key)
+		}
+	}
+
+	changes = append(changes, namedListChanges(n["flows"], p["flows"], "name", "flow", func(label string, planned, prior map[string]interface{}) []string {
+		var lines []string
+		if keys := changedKeys(planned, prior, "steps"); len(keys) > 0 {
+			lines = append(lines, fmt.Sprintf("~ %s: %s", label, strings.Join(keys, ", ")))
+		}
+		return append(lines, stepChanges(planned["steps"], prior["steps"], label+" ")...)
+	})...)
+	// Definitions predating flows list a single flow's steps at the top level.
+	changes = append(changes, stepChanges(n["steps"], p["steps"], "")...)
+
+	changes = append(changes, namedListChanges(n["requiredConfigVars"], p["requiredConfigVars"], "key", "config variable", keyChanges)...)
+	changes = append(changes, namedListChanges(n["configPages"], p["configPages"], "name", "config page", keyChanges)...)
+	return changes, nil
+}
+
+func stepChanges(planned, prior interface{}, prefix string) []string {
+	return namedListChanges(planned, prior, "name", prefix+"step", keyChanges)
+}
+
+func keyChanges(label string, planned, prior map[string]interface{}) []string {
+	if keys := changedKeys(planned, prior); len(keys) > 0 {
+		return []string{fmt.Sprintf("~ %s: %s", label, strings.Join(keys, ", "))}
+	}
+	return nil
+}
+
+// namedListChanges matches the maps in two YAML lists by their id field (such as
+// a flow's name) and reports additions, removals, and the changes diff finds in
+// items present in both. Items are labeled like `flow "Name"`. As with
+// yamlSubset, omitting the list entirely leaves it as the API has it.
+func namedListChanges(planned, prior interface{}, id, kind string, diff func(label string, planned, prior map[string]interface{}) []string) []string {
+	if planned == nil {
+		return nil
+	}
+	plannedOrder, plannedItems := namedItems(planned, id)
+	priorOrder, priorItems := namedItems(prior, id)
+
+	var changes []string
+	for _, name := range plannedOrder {
+		label := fmt.Sprintf("%s %q", kind, name)
+		priorItem, ok := priorItems[name]
+		if !ok {
+			changes = append(changes, "+ "+label)
+			continue
+		}
+		changes = append(changes, diff(label, plannedItems[name], priorItem)...)
+	}
+	for _, name := range priorOrder {
+		if _, ok := plannedItems[name]; !ok {
+			changes = append(changes, fmt.Sprintf("- %s %q", kind, name))
+		}
+	}
+	return changes
+}
+
+// namedItems indexes the maps in a YAML list by their id field, also returning the
+// ids in list order. Items without an id are skipped.
+func namedItems(list interface{}, id string) ([]string, map[string]map[string]interface{}) {
+	items, _ := list.([]interface{})
+	var order []string
+	byName := make(map[string]map[string]interface{}, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok || m[id] == nil {
+			continue
+		}
+		name := fmt.Sprint(m[id])
+		if _, dup := byName[name]; !dup {
+			order = append(order, name)
+		}
+		byName[name] = m
+	}
+	return order, byName
+}
+
+// changedKeys lists, sorted, the keys of planned whose values are not a
+// yamlSubset of the prior values, other than those in skip.
+func changedKeys(planned, prior map[string]interface{}, skip ...string) []string {
+	var keys []string
+	for key, value := range planned {
+		if containsString(skip, key) {
+			continue
+		}
+		if priorValue, ok := prior[key]; ok {
+			if key == "version" && fmt.Sprint(value) == "LATEST" {
+				continue
+			}
+			if yamlSubset(value, priorValue) {
+				continue
+			}
+		} else if yamlEmpty(value) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isYAMLScalar(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	default:
+		return true
+	}
+}
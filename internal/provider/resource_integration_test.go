@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -65,6 +66,30 @@ EOF
 }`, definition)
 }
 
+// splitDefinitionDirectory holds a definition split into a file of top-level keys
+// and a file per flow.
+var splitDefinitionDirectory = filepath.Join("..", "..", "test", "data", "integrations", "split")
+
+// resourceWithDefinitionFiles configures the integration from source, either
+// "definition_directory" or "definition_files", naming the split definition by
+// absolute paths since Terraform runs in a directory of its own.
+func resourceWithDefinitionFiles(t *testing.T, source string) string {
+	dir, err := filepath.Abs(splitDefinitionDirectory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source == "definition_directory" {
+		return fmt.Sprintf(`
+resource "prismatic_integration" "integration" {
+  definition_directory = %q
+}`, dir)
+	}
+	return fmt.Sprintf(`
+resource "prismatic_integration" "integration" {
+  definition_files = [%q, %q, %q]
+}`, filepath.Join(dir, "integration.yml"), filepath.Join(dir, "flows", "flow-1.yml"), filepath.Join(dir, "flows", "flow-2.yml"))
+}
+
 func TestAccResourceIntegration_basic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -135,6 +160,39 @@ func TestAccResourceIntegration_planStability(t *testing.T) {
 	})
 }
 
+func TestAccResourceIntegration_definitionFiles(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckIntegrationResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: resourceWithDefinitionFiles(t, "definition_directory"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "definition_hash"),
+					resource.TestCheckResourceAttr(resourceName, "name", "Acceptance Test Split"),
+					resource.TestCheckResourceAttr(resourceName, "description", expectedDescription),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				// The same files listed in the same order merge to the same definition.
+				Config: resourceWithDefinitionFiles(t, "definition_files"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "definition_hash"),
+					resource.TestCheckResourceAttr(resourceName, "definition_files.#", "3"),
+					resource.TestCheckNoResourceAttr(resourceName, "definition_directory"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckIntegrationResourceDestroy(s *terraform.State) error {
 	client, err := testAccGraphQLClient()
 	if err != nil {
@@ -1,8 +1,21 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/compare"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/shurcooL/graphql"
 )
 
 // The exact v7 definition our acceptance test submits.
@@ -168,3 +181,590 @@ steps: []`
 		t.Fatalf("Did not suppress diff for logically identical definitions")
 	}
 }
+
+// TestDefinitionChanges checks the plan summary of a definition change against the
+// canonical read-back: normalization (resolved versions, injected defaults) is not
+// reported, while real flow, step and config variable changes are.
+func TestDefinitionChanges(t *testing.T) {
+	changes, err := definitionChanges(integrationCanonicalV7, integrationSubmittedV7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("equivalent definitions reported changes: %q", changes)
+	}
+
+	planned := strings.NewReplacer(
+		"name: Acceptance Test\n", "name: Acceptance Test Renamed\n",
+		"requiredConfigVars: []", "requiredConfigVars:\n  - key: apiKey\n    dataType: string",
+		"        inputs: {}", `        inputs:
+          body:
+            type: value
+            value: hello
+  - name: Flow 2
+    steps:
+      - name: Trigger
+        isTrigger: true`,
+	).Replace(integrationSubmittedV7)
+
+	changes, err = definitionChanges(integrationCanonicalV7, planned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`~ name: "Acceptance Test" -> "Acceptance Test Renamed"`,
+		`~ flow "Flow 1" step "Integration Trigger": inputs`,
+		`+ flow "Flow 2"`,
+		`+ config variable "apiKey"`,
+	}
+	if strings.Join(changes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got changes:\n%s\nwant:\n%s", strings.Join(changes, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Removals, and steps of definitions predating flows. Omitting a list leaves it
+	// unchanged; an explicitly empty one removes its items.
+	prior := "name: Old\nsteps:\n  - name: A\n  - name: B\n    inputs: {x: 1}\nrequiredConfigVars:\n  - key: gone\n"
+	changes, err = definitionChanges(prior, "name: Old\nsteps:\n  - name: B\n    inputs: {x: 2}\nrequiredConfigVars: []\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{`~ step "B": inputs`, `- step "A"`, `- config variable "gone"`}
+	if strings.Join(changes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got changes:\n%s\nwant:\n%s", strings.Join(changes, "\n"), strings.Join(want, "\n"))
+	}
+
+	if _, err := definitionChanges(integrationCanonicalV7, "flows: ["); err == nil {
+		t.Error("expected an error for an unparseable definition")
+	}
+}
+
+func TestValidateIntegrationDefinition(t *testing.T) {
+	for _, file := range []string{"pokemon.yml", "test.yml"} {
+		content, err := os.ReadFile(filepath.Join("..", "..", "test", "data", "integrations", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if errs := validateIntegrationDefinition(string(content)); len(errs) != 0 {
+			t.Errorf("%s: unexpected errors %v", file, errs)
+		}
+	}
+	for name, definition := range map[string]string{
+		"submitted v7": integrationSubmittedV7,
+		"canonical v7": integrationCanonicalV7,
+	} {
+		if errs := validateIntegrationDefinition(definition); len(errs) != 0 {
+			t.Errorf("%s: unexpected errors %v", name, errs)
+		}
+	}
+
+	cases := []struct {
+		name       string
+		definition string
+		want       []string
+	}{
+		{
+			name:       "invalid YAML",
+			definition: "name: Test\nflows: [\n",
+			want:       []string{"line 2, column 1: invalid YAML: did not find expected node content"},
+		},
+		{
+			name:       "not a map",
+			definition: "- name: Test\n",
+			want:       []string{"line 1, column 1: expected an integration definition, got a list"},
+		},
+		{
+			name:       "no flows or steps",
+			definition: "name: Test\ndescription: No flows\n",
+			want:       []string{`line 1, column 1: an integration definition must have flows, or steps and trigger`},
+		},
+		{
+			name: "flow problems",
+			definition: `name: Test
+flows:
+  - name: Flow 1
+    steps:
+      - name: Step
+        action:
+          component: {key: http}
+  - steps: none
+`,
+			want: []string{
+				`line 7, column 11: flows[0].steps[0].action: an action is missing required key "key"`,
+				`line 7, column 22: flows[0].steps[0].action.component: a component reference is missing required key "version"`,
+				`line 5, column 7: flows[0].steps: must contain a trigger step (isTrigger true)`,
+				`line 8, column 5: flows[1]: a flow is missing required key "name"`,
+				`line 8, column 12: flows[1].steps: expected array, got "none"`,
+			},
+		},
+		{
+			name: "config variables and old-format trigger",
+			definition: `name: Test
+requiredConfigVars:
+  - dataType: string
+steps: []
+trigger: {schedule: null}
+`,
+			want: []string{
+				`line 3, column 5: requiredConfigVars[0]: a config variable is missing required key "key"`,
+				`line 5, column 10: trigger: a trigger is missing required key "name"`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, err := range validateIntegrationDefinition(tc.definition) {
+				got = append(got, err.Error())
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("got errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestMergeDefinitionFiles(t *testing.T) {
+	files := []definitionFile{
+		{Name: "integration.yml", Content: []byte("name: Test\ndescription: Split\nrequiredConfigVars:\n  - key: Token\n")},
+		{Name: "flows/a.yml", Content: []byte("flows:\n  - name: A\n    steps: &steps\n      - name: Trigger\n        isTrigger: true\n        action: {key: webhook, componentKey: webhook-triggers}\n")},
+		{Name: "flows/b.yml", Content: []byte("name: Test\nrequiredConfigVars:\n  - key: Url\n---\nflows:\n  - name: B\n    steps:\n      - name: Trigger\n        isTrigger: true\n        action: {key: webhook, componentKey: webhook-triggers}\n")},
+	}
+	merged, errs := mergeDefinitionFiles(files)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if errs := merged.validate(); len(errs) != 0 {
+		t.Fatalf("unexpected validation errors %v", errs)
+	}
+	got, err := merged.String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `name: Test
+description: Split
+requiredConfigVars:
+  - key: Token
+  - key: Url
+flows:
+  - name: A
+    steps:
+      - name: Trigger
+        isTrigger: true
+        action: {key: webhook, componentKey: webhook-triggers}
+  - name: B
+    steps:
+      - name: Trigger
+        isTrigger: true
+        action: {key: webhook, componentKey: webhook-triggers}
+`
+	if got != want {
+		t.Errorf("got merged definition:\n%s\nwant:\n%s", got, want)
+	}
+	if definitionHash(got) != definitionHash(want) || definitionHash(got) == definitionHash(want+"\n") {
+		t.Error("definitionHash does not track the definition text")
+	}
+
+	cases := []struct {
+		name  string
+		files []definitionFile
+		want  []string
+	}{
+		{
+			name: "conflicting scalar",
+			files: []definitionFile{
+				{Name: "a.yml", Content: []byte("name: Test\n")},
+				{Name: "b.yml", Content: []byte("description: B\nname: Other\n")},
+			},
+			want: []string{`b.yml, line 2, column 7: name: "Other" conflicts with "Test" at line 1 of a.yml`},
+		},
+		{
+			name: "conflicting kinds",
+			files: []definitionFile{
+				{Name: "a.yml", Content: []byte("flows: []\n")},
+				{Name: "b.yml", Content: []byte("flows:\n  name: A\n")},
+			},
+			want: []string{`b.yml, line 2, column 3: flows: a map conflicts with a list at line 1 of a.yml`},
+		},
+		{
+			name: "invalid YAML",
+			files: []definitionFile{
+				{Name: "a.yml", Content: []byte("name: Test\n")},
+				{Name: "b.yml", Content: []byte("flows: [\n")},
+			},
+			want: []string{"b.yml, line 1, column 1: invalid YAML: did not find expected node content"},
+		},
+		{
+			name:  "empty",
+			files: []definitionFile{{Name: "a.yml", Content: []byte("# nothing yet\n")}},
+			want:  []string{"a.yml, line 1, column 1: the definition is empty"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			merged, errs := mergeDefinitionFiles(tc.files)
+			if merged != nil {
+				t.Fatal("expected no merged definition")
+			}
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("got errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+			}
+		})
+	}
+
+	// Validation errors point at the file the offending YAML came from.
+	merged, errs = mergeDefinitionFiles([]definitionFile{
+		{Name: "integration.yml", Content: []byte("name: Test\n")},
+		{Name: "flows/a.yml", Content: []byte("flows:\n  - name: A\n    steps:\n      - name: Trigger\n        action: {key: webhook, componentKey: webhook-triggers}\n")},
+	})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	errs = merged.validate()
+	if len(errs) != 1 || errs[0].Error() != "flows/a.yml, line 4, column 7: flows[0].steps: must contain a trigger step (isTrigger true)" {
+		t.Errorf("got validation errors %v", errs)
+	}
+}
+
+func TestReadDefinitionDirectory(t *testing.T) {
+	files, err := readDefinitionDirectory(filepath.Join("..", "..", "test", "data", "integrations", "split"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	if got, want := strings.Join(names, ","), "flows/flow-1.yml,flows/flow-2.yml,integration.yml"; got != want {
+		t.Errorf("read files %s, want %s", got, want)
+	}
+	merged, errs := mergeDefinitionFiles(files)
+	if len(errs) == 0 {
+		errs = merged.validate()
+	}
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"README.md":       "# Integration",
+		".hidden.yml":     "name: [",
+		".git/config.yml": "name: [",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := readDefinitionDirectory(dir); err == nil || !strings.Contains(err.Error(), "contains no .yml or .yaml files") {
+		t.Errorf("got error %v, want no YAML files", err)
+	}
+	if _, err := readDefinitionFiles(nil); err == nil {
+		t.Error("expected an error for no files")
+	}
+}
+
+func TestPinComponentVersions(t *testing.T) {
+	definition := `name: Pinned
+requiredConfigVars:
+  - key: Connection
+    dataType: connection
+    connection:
+      component: {key: connection-owner, isPublic: false}
+      key: oauth
+flows:
+  - name: Flow 1
+    steps:
+      - name: Trigger
+        isTrigger: true
+        action:
+          component: {key: webhook-triggers, version: LATEST, isPublic: true}
+          key: webhook
+      - name: Step
+        action:
+          component: &private {key: componentKey, version: LATEST, isPublic: false}
+          key: actionKey
+      - name: Again
+        action:
+          component: *private
+          key: otherAction
+`
+	got, unmatched, err := pinComponentVersions(definition, map[string]int64{
+		"componentKey":     3,
+		"connection-owner": 7,
+		"webhook-triggers": 2,
+		"unused":           1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `name: Pinned
+requiredConfigVars:
+  - key: Connection
+    dataType: connection
+    connection:
+      component: {key: connection-owner, isPublic: false, version: 7}
+      key: oauth
+flows:
+  - name: Flow 1
+    steps:
+      - name: Trigger
+        isTrigger: true
+        action:
+          component: {key: webhook-triggers, version: LATEST, isPublic: true}
+          key: webhook
+      - name: Step
+        action:
+          component: &private {key: componentKey, version: 3, isPublic: false}
+          key: actionKey
+      - name: Again
+        action:
+          component: *private
+          key: otherAction
+`
+	if got != want {
+		t.Errorf("got pinned definition:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Join(unmatched, ",") != "unused,webhook-triggers" {
+		t.Errorf("got unmatched keys %v", unmatched)
+	}
+	if !definitionsEquivalent(definition, got) {
+		t.Error("a definition pinning LATEST versions should be equivalent to the pinned definition")
+	}
+
+	if unchanged, _, err := pinComponentVersions(definition, nil); err != nil || unchanged != definition {
+		t.Errorf("got %q, %v without versions, want the definition unchanged", unchanged, err)
+	}
+	if _, _, err := pinComponentVersions("flows: [", map[string]int64{"componentKey": 1}); err == nil {
+		t.Error("expected an error for an unparseable definition")
+	}
+}
+
+func TestUnitResourceIntegration_lifecycle(t *testing.T) {
+	testUnitPreCheck(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckIntegrationResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: resourceWithDefinition(baseDefinition),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "name", expectedName),
+					resource.TestCheckResourceAttr(resourceName, "description", expectedDescription),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: resourceWithDefinition(updateDefinition),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", expectedUpdatedName),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"definition"},
+			},
+		},
+	})
+}
+
+// TestUnitResourceIntegration_timeoutsOnly checks that changing only the timeouts
+// does not re-import the definition, which would replace the draft's flows.
+func TestUnitResourceIntegration_timeoutsOnly(t *testing.T) {
+	testUnitPreCheck(t)
+
+	config := func(timeouts string) string {
+		return strings.TrimSuffix(resourceWithDefinition(baseDefinition), "}") + timeouts + `}
+
+data "prismatic_integration" "integration" {
+  id         = prismatic_integration.integration.id
+  depends_on = [prismatic_integration.integration]
+}`
+	}
+	sameFlow := statecheck.CompareValue(compare.ValuesSame())
+	flowId := tfjsonpath.New("flows").AtSliceIndex(0).AtMapKey("id")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckIntegrationResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					sameFlow.AddStateValue(integrationDataSourceName, flowId),
+				},
+			},
+			{
+				Config: config(`
+  timeouts {
+    update = "10m"
+  }
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					sameFlow.AddStateValue(integrationDataSourceName, flowId),
+				},
+				Check: resource.TestCheckResourceAttr(resourceName, "timeouts.update", "10m"),
+			},
+		},
+	})
+}
+
+func TestUnitResourceIntegration_definitionFiles(t *testing.T) {
+	testUnitPreCheck(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckIntegrationResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: resourceWithDefinitionFiles(t, "definition_directory"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "definition_hash"),
+					resource.TestCheckResourceAttr(resourceName, "name", "Acceptance Test Split"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: resourceWithDefinitionFiles(t, "definition_files"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "definition_files.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitResourceIntegration_componentVersions(t *testing.T) {
+	server := testUnitPreCheck(t)
+
+	config := `
+data "prismatic_component_bundle" "bundle" {
+    bundle_directory = "../../test/data/component/code"
+    bundle_path = "../../test/data/component/bundle.zip"
+}
+
+resource "prismatic_component" "component" {
+    bundle_directory = data.prismatic_component_bundle.bundle.bundle_directory
+    bundle_path = data.prismatic_component_bundle.bundle.bundle_path
+    signature = data.prismatic_component_bundle.bundle.signature
+    manifest_path = "component.json"
+    deletion_policy = "delete"
+}
+
+resource "prismatic_integration" "integration" {
+  component_versions = {
+    (prismatic_component.component.key) = prismatic_component.component.version_number
+  }
+  definition = <<EOF
+name: Uses componentKey
+flows:
+  - name: Flow 1
+    steps:
+      - name: Trigger
+        isTrigger: true
+        action:
+          component: {key: webhook-triggers, version: LATEST, isPublic: true}
+          key: webhook
+      - name: Step
+        action:
+          component: {key: componentKey, version: LATEST, isPublic: false}
+          key: actionKey
+EOF
+}`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckIntegrationResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "component_versions.componentKey", "1"),
+					testUnitCheckIntegrationDefinitionContains(resourceName, "{key: componentKey, version: 1, isPublic: false}"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// Republishing the component re-imports the integration with the new version.
+			{
+				PreConfig: func() {
+					if err := server.RepublishComponent("componentKey", "published-elsewhere"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "component_versions.componentKey", "3"),
+					testUnitCheckIntegrationDefinitionContains(resourceName, "{key: componentKey, version: 3, isPublic: false}"),
+				),
+			},
+			{
+				Config:      strings.Replace(config, "(prismatic_component.component.key)", `"otherKey"`, 1),
+				ExpectError: regexp.MustCompile(`no reference to a private component with key "otherKey"`),
+			},
+		},
+	})
+}
+
+// testUnitCheckIntegrationDefinitionContains checks the definition the API holds
+// for the integration, which state does not show once component versions are
+// pinned.
+func testUnitCheckIntegrationDefinitionContains(name, want string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found", name)
+		}
+		client, err := testAccGraphQLClient()
+		if err != nil {
+			return err
+		}
+		var query struct {
+			Integration struct {
+				Definition string
+			} `graphql:"integration(id: $id)"`
+		}
+		if err := client.Query(context.Background(), &query, map[string]interface{}{"id": graphql.ID(rs.Primary.ID)}); err != nil {
+			return err
+		}
+		if !strings.Contains(query.Integration.Definition, want) {
+			return fmt.Errorf("definition does not contain %q:\n%s", want, query.Integration.Definition)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/prismatic-io/terraform-provider-prismatic/internal/util"
+	"github.com/shurcooL/graphql"
+)
+
+var (
+	_ resource.Resource              = (*integrationVersionResource)(nil)
+	_ resource.ResourceWithConfigure = (*integrationVersionResource)(nil)
+)
+
+type integrationVersionResource struct {
+	client *graphql.Client
+}
+
+type integrationVersionResourceModel struct {
+	Id            types.String   `tfsdk:"id"`
+	IntegrationId types.String   `tfsdk:"integration_id"`
+	Comment       types.String   `tfsdk:"comment"`
+	Definition    types.String   `tfsdk:"definition"`
+	VersionNumber types.Int64    `tfsdk:"version_number"`
+	PublishedAt   types.String   `tfsdk:"published_at"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+type PublishIntegrationInput struct {
+	Id       graphql.ID     `json:"id"`
+	Comments graphql.String `json:"comments,omitempty"`
+}
+
+func (r *integrationVersionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_integration_version"
+}
+
+func (r *integrationVersionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Publish a version of an Integration in Prismatic. Every attribute forces a new version to be published, so referencing the Integration's definition publishes a new version whenever it changes.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the published Integration version",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"integration_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the Integration to publish",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:    true,
+				Description: "A comment describing the published version",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"definition": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Integration definition being published. It is not sent to Prismatic; reference the `definition` of the `prismatic_integration` resource so that a definition change publishes a new version.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version_number": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The version number assigned to the published version",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"published_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "The timestamp when the version was published",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+			}),
+		},
+	}
+}
+
+func (r *integrationVersionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *integrationVersionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan integrationVersionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mutation struct {
+		PublishIntegration struct {
+			Integration struct {
+				Id graphql.ID
+			}
+			Errors util.GqlErrors
+		} `graphql:"publishIntegration(input: $input)"`
+	}
+	variables := map[string]interface{}{
+		"input": PublishIntegrationInput{
+			Id:       graphql.ID(plan.IntegrationId.ValueString()),
+			Comments: graphql.String(plan.Comment.ValueString()),
+		},
+	}
+
+	if err := r.client.Mutate(ctx, &mutation, variables); err != nil {
+		resp.Diagnostics.AddError("Unable to publish integration", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(gqlErrorDiagnostics(mutation.PublishIntegration.Errors)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := r.read(ctx, mutation.PublishIntegration.Integration.Id.(string), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state == nil {
+		resp.Diagnostics.AddError("Unable to read integration version", "Integration version was published but could not be found.")
+		return
+	}
+	state.copyPublishInputsFrom(plan)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *integrationVersionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state integrationVersionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updated := r.read(ctx, state.Id.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if updated == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	updated.copyPublishInputsFrom(state)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, updated)...)
+}
+
+// Update is intentionally a no-op. Every configurable attribute forces
+// replacement, so an in-place update only ever records changed timeouts.
+func (r *integrationVersionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan integrationVersionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *integrationVersionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Published versions cannot be unpublished, and instances may still be deployed
+	// with this one, so only clear state.
+	resp.State.RemoveResource(ctx)
+}
+
+// read queries the published version by its id and maps it into a model, returning
+// nil if it no longer exists. The publish inputs are not part of the API response,
+// so the caller restores them with copyPublishInputsFrom.
+func (r *integrationVersionResource) read(ctx context.Context, id string, diags *diag.Diagnostics) *integrationVersionResourceModel {
+	var query struct {
+		Integration struct {
+			Id               graphql.ID
+			VersionNumber    graphql.Int
+			VersionCreatedAt graphql.String
+		} `graphql:"integration(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id": graphql.ID(id),
+	}
+	if err := r.client.Query(ctx, &query, variables); err != nil {
+		if isRecordNotFound(err) {
+			return nil
+		}
+		diags.AddError("Unable to read integration version", err.Error())
+		return nil
+	}
+
+	return &integrationVersionResourceModel{
+		Id:            types.StringValue(query.Integration.Id.(string)),
+		VersionNumber: types.Int64Value(int64(query.Integration.VersionNumber)),
+		PublishedAt:   types.StringValue(string(query.Integration.VersionCreatedAt)),
+	}
+}
+
+// copyPublishInputsFrom carries the configuration-only publish inputs over from the
+// plan or prior state into a freshly read model.
+func (m *integrationVersionResourceModel) copyPublishInputsFrom(src integrationVersionResourceModel) {
+	m.IntegrationId = src.IntegrationId
+	m.Comment = src.Comment
+	m.Definition = src.Definition
+	m.Timeouts = src.Timeouts
+}
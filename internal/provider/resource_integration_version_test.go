@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+const integrationVersionResourceName = "prismatic_integration_version.version"
+
+func integrationVersionConfig(definition, comment string) string {
+	return fmt.Sprintf(`
+resource "prismatic_integration" "integration" {
+  definition = <<EOF
+%s
+EOF
+}
+
+resource "prismatic_integration_version" "version" {
+  integration_id = prismatic_integration.integration.id
+  definition     = prismatic_integration.integration.definition
+  comment        = %q
+}`, definition, comment)
+}
+
+func TestAccResourceIntegrationVersion_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckIntegrationResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: integrationVersionConfig(baseDefinition, "Initial version"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(integrationVersionResourceName, "id"),
+					resource.TestCheckResourceAttrSet(integrationVersionResourceName, "version_number"),
+					resource.TestCheckResourceAttrSet(integrationVersionResourceName, "published_at"),
+					resource.TestCheckResourceAttr(integrationVersionResourceName, "comment", "Initial version"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// A definition change publishes a new version.
+			{
+				Config: integrationVersionConfig(updateDefinition, "Initial version"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(integrationVersionResourceName, plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(integrationVersionResourceName, "version_number"),
+				),
+			},
+		},
+	})
+}
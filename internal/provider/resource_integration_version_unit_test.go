@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+// integrationVersionTimeoutsConfig is integrationVersionConfig with a create
+// timeout on the published version.
+func integrationVersionTimeoutsConfig(definition, comment, create string) string {
+	return fmt.Sprintf(`
+resource "prismatic_integration" "integration" {
+  definition = <<EOF
+%s
+EOF
+}
+
+resource "prismatic_integration_version" "version" {
+  integration_id = prismatic_integration.integration.id
+  definition     = prismatic_integration.integration.definition
+  comment        = %q
+
+  timeouts {
+    create = %q
+  }
+}`, definition, comment, create)
+}
+
+func TestUnitResourceIntegrationVersion_lifecycle(t *testing.T) {
+	testUnitPreCheck(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckIntegrationResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: integrationVersionConfig(baseDefinition, "Initial version"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(integrationVersionResourceName, "version_number", "1"),
+					resource.TestCheckResourceAttrSet(integrationVersionResourceName, "published_at"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: integrationVersionConfig(updateDefinition, "Initial version"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(integrationVersionResourceName, plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(integrationVersionResourceName, "version_number", "2"),
+				),
+			},
+			// Changing only the timeouts updates in place and keeps the
+			// published version's computed attributes.
+			{
+				Config: integrationVersionTimeoutsConfig(updateDefinition, "Initial version", "10m"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(integrationVersionResourceName, plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(integrationVersionResourceName, "version_number", "2"),
+					resource.TestCheckResourceAttrSet(integrationVersionResourceName, "id"),
+					resource.TestCheckResourceAttrSet(integrationVersionResourceName, "published_at"),
+					resource.TestCheckResourceAttr(integrationVersionResourceName, "timeouts.create", "10m"),
+				),
+			},
+		},
+	})
+}
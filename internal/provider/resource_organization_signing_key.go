@@ -4,10 +4,12 @@ import (
 	"context"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -31,6 +33,7 @@ type organizationSigningKeyResourceModel struct {
 	PublicKey normalizedStringValue `tfsdk:"public_key"`
 	Imported  types.Bool            `tfsdk:"imported"`
 	IssuedAt  types.String          `tfsdk:"issued_at"`
+	Timeouts  timeouts.Value        `tfsdk:"timeouts"`
 }
 
 func (r *organizationSigningKeyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -52,16 +55,32 @@ func (r *organizationSigningKeyResource) Schema(ctx context.Context, req resourc
 			"imported": schema.BoolAttribute{
 				Computed:    true,
 				Description: "Indicates if signing key was imported or generated",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"issued_at": schema.StringAttribute{
 				Computed:    true,
 				Description: "Timestamp of when the signing key was issued",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"id": schema.StringAttribute{
 				Computed:    true,
 				Description: "The ID of the signing key",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -72,6 +91,8 @@ func (r *organizationSigningKeyResource) Configure(ctx context.Context, req reso
 func (r *organizationSigningKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan organizationSigningKeyResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -111,6 +132,7 @@ func (r *organizationSigningKeyResource) Create(ctx context.Context, req resourc
 		resp.Diagnostics.AddError("Unable to read organization signing key", "Signing key was imported but could not be found.")
 		return
 	}
+	state.Timeouts = plan.Timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
@@ -118,6 +140,8 @@ func (r *organizationSigningKeyResource) Create(ctx context.Context, req resourc
 func (r *organizationSigningKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state organizationSigningKeyResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -130,12 +154,13 @@ func (r *organizationSigningKeyResource) Read(ctx context.Context, req resource.
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	found.Timeouts = state.Timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, found)...)
 }
 
 // Update is intentionally a no-op. The only configurable attribute (public_key)
-// forces replacement, so an in-place update is never requested.
+// forces replacement, so an in-place update only ever records changed timeouts.
 func (r *organizationSigningKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan organizationSigningKeyResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -184,6 +209,8 @@ func (r *organizationSigningKeyResource) read(ctx context.Context, id string, di
 func (r *organizationSigningKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state organizationSigningKeyResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
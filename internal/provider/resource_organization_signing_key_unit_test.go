@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestUnitResourceOrganizationSigningKey_lifecycle(t *testing.T) {
+	testUnitPreCheck(t)
+
+	resourceName := "prismatic_organization_signing_key.key"
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckOrganizationSigningKeyResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: resourceWithPubkey(expectedPubKey),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttr(resourceName, "public_key", expectedPubKey),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Changing only the timeouts updates in place and keeps the key's
+			// computed attributes.
+			{
+				Config: strings.TrimSuffix(resourceWithPubkey(expectedPubKey), "}") + `
+  timeouts {
+    create = "10m"
+  }
+}`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "issued_at"),
+					resource.TestCheckResourceAttr(resourceName, "timeouts.create", "10m"),
+				),
+			},
+		},
+	})
+}
@@ -2,8 +2,11 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"regexp"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -21,22 +24,29 @@ var (
 	_ resource.Resource                = (*organizationUserResource)(nil)
 	_ resource.ResourceWithConfigure   = (*organizationUserResource)(nil)
 	_ resource.ResourceWithImportState = (*organizationUserResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*organizationUserResource)(nil)
 )
 
+// organizationUserEmailImportPrefix marks an import ID as a user's email address
+// rather than a Prismatic ID.
+const organizationUserEmailImportPrefix = "email:"
+
 type organizationUserResource struct {
 	client *graphql.Client
 }
 
 type organizationUserResourceModel struct {
-	Id         types.String `tfsdk:"id"`
-	Email      types.String `tfsdk:"email"`
-	Name       types.String `tfsdk:"name"`
-	Role       types.String `tfsdk:"role"`
-	Phone      types.String `tfsdk:"phone"`
-	ExternalId types.String `tfsdk:"external_id"`
-	AvatarUrl  types.String `tfsdk:"avatar_url"`
-	CreatedAt  types.String `tfsdk:"created_at"`
-	UpdatedAt  types.String `tfsdk:"updated_at"`
+	Id         types.String   `tfsdk:"id"`
+	Email      types.String   `tfsdk:"email"`
+	Name       types.String   `tfsdk:"name"`
+	Role       types.String   `tfsdk:"role"`
+	RoleName   types.String   `tfsdk:"role_name"`
+	Phone      types.String   `tfsdk:"phone"`
+	ExternalId types.String   `tfsdk:"external_id"`
+	AvatarUrl  types.String   `tfsdk:"avatar_url"`
+	CreatedAt  types.String   `tfsdk:"created_at"`
+	UpdatedAt  types.String   `tfsdk:"updated_at"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
 }
 
 // e164PhoneValidator validates that a phone number is in E.164 format.
@@ -87,7 +97,7 @@ func (r *organizationUserResource) Schema(ctx context.Context, req resource.Sche
 			},
 			"email": schema.StringAttribute{
 				Required:    true,
-				Description: "The email address of the user. Changing this will recreate the user.",
+				Description: "The email address of the user. Changing this will recreate the user. Users can be imported by email using an import ID of the form `email:<address>`.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -103,7 +113,11 @@ func (r *organizationUserResource) Schema(ctx context.Context, req resource.Sche
 			},
 			"role": schema.StringAttribute{
 				Required:    true,
-				Description: "The ID of the role to assign to the user.",
+				Description: "The ID or name of the role to assign to the user, such as `Admin`. Names are resolved to the Organization's roles at plan time. Imported users refer to their role by name unless another role shares it.",
+			},
+			"role_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the role assigned to the user.",
 			},
 			"phone": schema.StringAttribute{
 				Optional: true,
@@ -140,6 +154,14 @@ func (r *organizationUserResource) Schema(ctx context.Context, req resource.Sche
 				Description: "The timestamp when the user was last updated.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -147,9 +169,109 @@ func (r *organizationUserResource) Configure(ctx context.Context, req resource.C
 	r.client = clientFromProviderData(req.ProviderData, &resp.Diagnostics)
 }
 
+// ModifyPlan resolves the configured role, by ID or by name, to one of the
+// Organization's roles so an unknown role fails the plan rather than the apply,
+// and plans role_name from it.
+func (r *organizationUserResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan organizationUserResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.Role.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("role_name"), types.StringUnknown())...)
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state organizationUserResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if plan.Role.Equal(state.Role) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("role_name"), state.RoleName)...)
+			return
+		}
+	}
+	// The provider is not configured yet when its settings are unknown.
+	if r.client == nil {
+		return
+	}
+
+	role := r.findRole(ctx, plan.Role.ValueString(), &resp.Diagnostics)
+	if role == nil {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("role_name"), role.name)...)
+}
+
+// organizationRole is one of the Organization's roles.
+type organizationRole struct {
+	id   string
+	name string
+}
+
+// findRole returns the Organization role whose ID or name is ref, recording an
+// attribute error on role when there is none.
+func (r *organizationUserResource) findRole(ctx context.Context, ref string, diags *diag.Diagnostics) *organizationRole {
+	roles := r.organizationRoles(ctx, diags)
+	if diags.HasError() {
+		return nil
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role.id == ref || role.name == ref {
+			return &role
+		}
+		names = append(names, role.name)
+	}
+	diags.AddAttributeError(
+		path.Root("role"),
+		"Unknown role",
+		fmt.Sprintf("No organization role has the ID or name %q. The roles are: %s.", ref, strings.Join(names, ", ")),
+	)
+	return nil
+}
+
+func (r *organizationUserResource) organizationRoles(ctx context.Context, diags *diag.Diagnostics) []organizationRole {
+	var query struct {
+		OrganizationRoles []struct {
+			Id   graphql.ID
+			Name graphql.String
+		} `graphql:"organizationRoles"`
+	}
+	if err := r.client.Query(ctx, &query, nil); err != nil {
+		diags.AddError("Unable to read organization roles", err.Error())
+		return nil
+	}
+
+	roles := make([]organizationRole, 0, len(query.OrganizationRoles))
+	for _, role := range query.OrganizationRoles {
+		roles = append(roles, organizationRole{id: role.Id.(string), name: string(role.Name)})
+	}
+	return roles
+}
+
+// keepRoleReference keeps the role as from refers to it, by ID or by name, while
+// it is still the user's role, so a role configured by name does not read back
+// as a change to its ID.
+func (m *organizationUserResourceModel) keepRoleReference(from organizationUserResourceModel) {
+	if !from.Role.IsNull() && !from.Role.IsUnknown() && from.Role.ValueString() == m.RoleName.ValueString() {
+		m.Role = from.Role
+	}
+}
+
 func (r *organizationUserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan organizationUserResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -163,9 +285,14 @@ func (r *organizationUserResource) Create(ctx context.Context, req resource.Crea
 		} `graphql:"createOrganizationUser(input: $input)"`
 	}
 
+	role := r.findRole(ctx, plan.Role.ValueString(), &resp.Diagnostics)
+	if role == nil {
+		return
+	}
+
 	input := CreateOrganizationUserInput{
 		Email: graphql.String(plan.Email.ValueString()),
-		Role:  graphql.ID(plan.Role.ValueString()),
+		Role:  graphql.ID(role.id),
 	}
 
 	if !plan.Name.IsNull() && !plan.Name.IsUnknown() {
@@ -202,6 +329,8 @@ func (r *organizationUserResource) Create(ctx context.Context, req resource.Crea
 		resp.Diagnostics.AddError("Unable to read organization user", "User was created but could not be found.")
 		return
 	}
+	state.keepRoleReference(plan)
+	state.Timeouts = plan.Timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
@@ -209,6 +338,8 @@ func (r *organizationUserResource) Create(ctx context.Context, req resource.Crea
 func (r *organizationUserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state organizationUserResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -221,6 +352,8 @@ func (r *organizationUserResource) Read(ctx context.Context, req resource.ReadRe
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	newState.keepRoleReference(state)
+	newState.Timeouts = state.Timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
 }
@@ -239,7 +372,8 @@ func (r *organizationUserResource) read(ctx context.Context, id string, diags *d
 			CreatedAt  graphql.String
 			UpdatedAt  graphql.String
 			Role       struct {
-				Id graphql.ID
+				Id   graphql.ID
+				Name graphql.String
 			}
 		} `graphql:"user(id: $id)"`
 	}
@@ -261,6 +395,7 @@ func (r *organizationUserResource) read(ctx context.Context, id string, diags *d
 		Email:      types.StringValue(string(query.User.Email)),
 		Name:       types.StringValue(string(query.User.Name)),
 		Role:       types.StringValue(query.User.Role.Id.(string)),
+		RoleName:   types.StringValue(string(query.User.Role.Name)),
 		Phone:      types.StringValue(string(query.User.Phone)),
 		ExternalId: types.StringValue(string(query.User.ExternalId)),
 		AvatarUrl:  types.StringValue(string(query.User.AvatarUrl)),
@@ -333,6 +468,8 @@ func (r *organizationUserResource) Update(ctx context.Context, req resource.Upda
 	var state organizationUserResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, plan.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -346,8 +483,16 @@ func (r *organizationUserResource) Update(ctx context.Context, req resource.Upda
 		} `graphql:"updateUser(input: $input)"`
 	}
 
+	input := buildUpdateUserInput(plan, state)
+	if input.Role != nil {
+		role := r.findRole(ctx, plan.Role.ValueString(), &resp.Diagnostics)
+		if role == nil {
+			return
+		}
+		input.Role = graphql.ID(role.id)
+	}
 	variables := map[string]interface{}{
-		"input": buildUpdateUserInput(plan, state),
+		"input": input,
 	}
 
 	if err := r.client.Mutate(ctx, &mutation, variables); err != nil {
@@ -368,6 +513,8 @@ func (r *organizationUserResource) Update(ctx context.Context, req resource.Upda
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	newState.keepRoleReference(plan)
+	newState.Timeouts = plan.Timeouts
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
 }
@@ -375,6 +522,8 @@ func (r *organizationUserResource) Update(ctx context.Context, req resource.Upda
 func (r *organizationUserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state organizationUserResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	ctx, cancel := withTimeout(ctx, state.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -402,6 +551,67 @@ func (r *organizationUserResource) Delete(ctx context.Context, req resource.Dele
 	resp.Diagnostics.Append(gqlErrorDiagnostics(mutation.DeleteUser.Errors)...)
 }
 
+// ImportState accepts either a Prismatic user ID or `email:<address>`, which is
+// resolved to the ID of the Organization user with that email address. The role
+// is imported by name, as configurations usually refer to it, unless another
+// Organization role shares the name.
 func (r *organizationUserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id := req.ID
+	if email, ok := strings.CutPrefix(req.ID, organizationUserEmailImportPrefix); ok {
+		id = r.findUserByEmail(ctx, email, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+
+	// Read reports a user that does not exist.
+	user := r.read(ctx, id, &resp.Diagnostics)
+	if user == nil || resp.Diagnostics.HasError() {
+		return
+	}
+	roles := r.organizationRoles(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	named := 0
+	for _, role := range roles {
+		if role.name == user.RoleName.ValueString() {
+			named++
+		}
+	}
+	if named == 1 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("role"), user.RoleName)...)
+	}
+}
+
+// findUserByEmail returns the ID of the Organization user with the given email
+// address, recording an error unless exactly one matches.
+func (r *organizationUserResource) findUserByEmail(ctx context.Context, email string, diags *diag.Diagnostics) string {
+	// Customer users may share the address, so only Organization users are matched.
+	var query struct {
+		Users struct {
+			Nodes []struct {
+				Id graphql.ID
+			}
+		} `graphql:"users(email: $email, customer_Isnull: true)"`
+	}
+	variables := map[string]interface{}{
+		"email": graphql.String(email),
+	}
+
+	if err := r.client.Query(ctx, &query, variables); err != nil {
+		diags.AddError("Unable to import user", err.Error())
+		return ""
+	}
+
+	switch len(query.Users.Nodes) {
+	case 0:
+		diags.AddError("Unable to import user", fmt.Sprintf("No organization user found with email %q.", email))
+	case 1:
+		return query.Users.Nodes[0].Id.(string)
+	default:
+		diags.AddError("Unable to import user", fmt.Sprintf("Found %d organization users with email %q; import by ID instead.", len(query.Users.Nodes), email))
+	}
+	return ""
 }
@@ -63,6 +63,56 @@ func TestAccResourceOrganizationUser_basic(t *testing.T) {
 				),
 			},
 			// Test import
+			{
+				ResourceName:      organizationUserResourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Imports refer to the role by name; this configuration uses its ID.
+				ImportStateVerifyIgnore: []string{"role"},
+			},
+			// Import by email
+			{
+				ResourceName:      organizationUserResourceName,
+				ImportState:       true,
+				ImportStateId:     organizationUserEmailImportPrefix + testUserEmail,
+				ImportStateVerify: true,
+				// Imports refer to the role by name; this configuration uses its ID.
+				ImportStateVerifyIgnore: []string{"role"},
+			},
+		},
+	})
+}
+
+func TestAccResourceOrganizationUser_roleByName(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckOrganizationUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: organizationUserConfig(testUserEmail, testUserName, `"Admin"`, "", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(organizationUserResourceName, "role", "Admin"),
+					resource.TestCheckResourceAttr(organizationUserResourceName, "role_name", "Admin"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// A configuration naming the role has nothing to change after import.
+			{
+				ResourceName:    organizationUserResourceName,
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithID,
+			},
+			{
+				ResourceName:    organizationUserResourceName,
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithID,
+				ImportStateId:   organizationUserEmailImportPrefix + testUserEmail,
+			},
 			{
 				ResourceName:      organizationUserResourceName,
 				ImportState:       true,
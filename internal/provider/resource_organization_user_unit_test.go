@@ -2,10 +2,15 @@ package provider
 
 import (
 	"context"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 	"github.com/shurcooL/graphql"
 )
 
@@ -138,3 +143,128 @@ func TestValidateE164Phone(t *testing.T) {
 		})
 	}
 }
+
+func TestUnitResourceOrganizationUser_lifecycle(t *testing.T) {
+	testUnitPreCheck(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckOrganizationUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: organizationUserConfig(testUserEmail, testUserName, "local.admin_role.id", "", "EXT-TEST-001"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(organizationUserResourceName, "id"),
+					resource.TestCheckResourceAttr(organizationUserResourceName, "email", testUserEmail),
+					resource.TestCheckResourceAttrSet(organizationUserResourceName, "created_at"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: organizationUserConfig(testUserEmail, testUserUpdatedName, "local.admin_role.id", "+15555550100", "EXT-TEST-001"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(organizationUserResourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(organizationUserResourceName, "name", testUserUpdatedName),
+					resource.TestCheckResourceAttr(organizationUserResourceName, "phone", "+15555550100"),
+				),
+			},
+			{
+				ResourceName:      organizationUserResourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Imports refer to the role by name; this configuration uses its ID.
+				ImportStateVerifyIgnore: []string{"role"},
+			},
+			{
+				ResourceName:      organizationUserResourceName,
+				ImportState:       true,
+				ImportStateId:     organizationUserEmailImportPrefix + testUserEmail,
+				ImportStateVerify: true,
+				// Imports refer to the role by name; this configuration uses its ID.
+				ImportStateVerifyIgnore: []string{"role"},
+			},
+			{
+				ResourceName:  organizationUserResourceName,
+				ImportState:   true,
+				ImportStateId: organizationUserEmailImportPrefix + "missing@example.com",
+				ExpectError:   regexp.MustCompile(`No organization user found with email "missing@example.com"`),
+			},
+		},
+	})
+}
+
+func TestUnitResourceOrganizationUser_roleByName(t *testing.T) {
+	testUnitPreCheck(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckOrganizationUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: organizationUserConfig(testUserEmail, testUserName, `"Admin"`, "", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(organizationUserResourceName, "role", "Admin"),
+					resource.TestCheckResourceAttr(organizationUserResourceName, "role_name", "Admin"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(organizationUserResourceName, tfjsonpath.New("role_name"), knownvalue.StringExact("Admin")),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// A configuration naming the role has nothing to change after import.
+			{
+				ResourceName:    organizationUserResourceName,
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithID,
+			},
+			{
+				ResourceName:    organizationUserResourceName,
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithID,
+				ImportStateId:   organizationUserEmailImportPrefix + testUserEmail,
+			},
+			{
+				ResourceName:      organizationUserResourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Switching to the same role by ID changes only how it is referenced.
+			{
+				Config: organizationUserConfig(testUserEmail, testUserName, "local.admin_role.id", "", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(organizationUserResourceName, "role", "data.prismatic_organization_roles.roles", "roles.1.id"),
+					resource.TestCheckResourceAttr(organizationUserResourceName, "role_name", "Admin"),
+				),
+			},
+			{
+				Config: organizationUserConfig(testUserEmail, testUserName, `"Member"`, "", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(organizationUserResourceName, plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue(organizationUserResourceName, tfjsonpath.New("role_name"), knownvalue.StringExact("Member")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(organizationUserResourceName, "role", "Member"),
+					resource.TestCheckResourceAttr(organizationUserResourceName, "role_name", "Member"),
+				),
+			},
+			{
+				Config:      organizationUserConfig(testUserEmail, testUserName, `"Superuser"`, "", ""),
+				ExpectError: regexp.MustCompile(`No organization role has the ID or name "Superuser"`),
+			},
+		},
+	})
+}
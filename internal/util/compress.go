@@ -2,59 +2,140 @@ package util
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 )
 
-func CompressDirectory(srcDirectory string, destPath string) (string, error) {
-	file, err := os.OpenFile(destPath, os.O_RDWR|os.O_CREATE, 0666)
+// bundleModTime is the modification time recorded for every bundle entry. It is
+// the earliest time a zip can represent, so that the same source tree always
+// produces the same archive regardless of checkout or build times.
+var bundleModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// CompressDirectory zips the files in srcDirectory selected by filter and the
+// directory's ignore file into destPath, replacing any existing archive, and
+// returns the archive's path along with the hex SHA-256 of each file keyed by its
+// path in the archive. The output is reproducible: entries are sorted by their
+// slash-separated relative path and carry a fixed timestamp and normalized
+// permissions, so identical trees yield byte-identical archives.
+func CompressDirectory(srcDirectory string, destPath string, filter BundleFilter) (string, map[string]string, error) {
+	files, err := listBundleFiles(srcDirectory, destPath, filter)
 	if err != nil {
-		return "", err
+		return "", nil, err
+	}
+
+	file, err := os.OpenFile(destPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return "", nil, err
 	}
 	defer func() { _ = file.Close() }()
 
+	fileHashes := make(map[string]string, len(files))
 	w := zip.NewWriter(file)
-	defer func() { _ = w.Close() }()
-
-	err = filepath.Walk(srcDirectory, createRecursiveWalker(w, srcDirectory))
-	if err != nil {
-		return "", err
+	for _, relPath := range files {
+		hash, err := addBundleFile(w, srcDirectory, relPath)
+		if err != nil {
+			_ = w.Close()
+			return "", nil, err
+		}
+		fileHashes[relPath] = hash
+	}
+	if err := w.Close(); err != nil {
+		return "", nil, err
 	}
 
-	return file.Name(), nil
+	return file.Name(), fileHashes, nil
 }
 
-func createRecursiveWalker(w *zip.Writer, rootPath string) func(path string, info os.FileInfo, err error) error {
-	return func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			return nil
-		}
+// listBundleFiles returns the slash-separated paths of the files under
+// srcDirectory selected for the bundle, relative to it and sorted, leaving out
+// destPath should the archive be written inside the directory being compressed.
+func listBundleFiles(srcDirectory string, destPath string, filter BundleFilter) ([]string, error) {
+	absDest, err := filepath.Abs(destPath)
+	if err != nil {
+		return nil, err
+	}
+	matcher, err := newBundleMatcher(srcDirectory, filter)
+	if err != nil {
+		return nil, err
+	}
 
-		file, err := os.Open(path)
+	var files []string
+	err = filepath.WalkDir(srcDirectory, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		defer func() { _ = file.Close() }()
-
-		relPath, err := filepath.Rel(rootPath, path)
+		relPath, err := filepath.Rel(srcDirectory, path)
 		if err != nil {
 			return err
 		}
+		relPath = filepath.ToSlash(relPath)
 
-		f, err := w.Create(relPath)
-		if err != nil {
-			return err
+		if d.IsDir() {
+			if relPath != "." && matcher.excludesDir(relPath) {
+				return filepath.SkipDir
+			}
+			return nil
 		}
-
-		_, err = io.Copy(f, file)
-		if err != nil {
-			return err
+		if absPath, err := filepath.Abs(path); err == nil && absPath == absDest {
+			return nil
+		}
+		if matcher.includesFile(relPath) {
+			files = append(files, relPath)
 		}
-
 		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// addBundleFile writes relPath into the archive and returns the hex SHA-256 of its
+// contents.
+func addBundleFile(w *zip.Writer, srcDirectory string, relPath string) (string, error) {
+	file, err := os.Open(filepath.Join(srcDirectory, filepath.FromSlash(relPath)))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	header := &zip.FileHeader{
+		Name:     relPath,
+		Method:   zip.Deflate,
+		Modified: bundleModTime,
+	}
+	header.SetMode(normalizedMode(info.Mode()))
+
+	f, err := w.CreateHeader(header)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// normalizedMode reduces a file's permissions to 0755 for executables and 0644
+// otherwise, so umask and checkout differences do not change the archive.
+func normalizedMode(mode fs.FileMode) fs.FileMode {
+	if mode.Perm()&0111 != 0 {
+		return 0755
 	}
+	return 0644
 }
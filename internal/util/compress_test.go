@@ -0,0 +1,196 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeBundleTree(t *testing.T, dir string, modTime time.Time, execMode os.FileMode) {
+	t.Helper()
+	files := map[string]string{
+		"index.js":          "module.exports = {};\n",
+		"package.json":      `{"name":"example"}`,
+		"lib/actions.js":    "exports.actions = [];\n",
+		"lib/nested/run.sh": "#!/bin/sh\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		mode := os.FileMode(0644)
+		if filepath.Ext(name) == ".sh" {
+			mode = execMode
+		}
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCompressDirectoryIsReproducible(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeBundleTree(t, first, time.Now(), 0755)
+	writeBundleTree(t, second, time.Now().Add(-72*time.Hour), 0700)
+
+	out := t.TempDir()
+	firstZip := filepath.Join(out, "first.zip")
+	secondZip := filepath.Join(out, "second.zip")
+
+	if _, _, err := CompressDirectory(first, firstZip, BundleFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := CompressDirectory(second, secondZip, BundleFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := os.ReadFile(firstZip)
+	b, _ := os.ReadFile(secondZip)
+	if !bytes.Equal(a, b) {
+		t.Fatal("bundles of identical trees differ")
+	}
+
+	r, err := zip.OpenReader(firstZip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+
+	want := []string{"index.js", "lib/actions.js", "lib/nested/run.sh", "package.json"}
+	if len(r.File) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(r.File), len(want))
+	}
+	for i, f := range r.File {
+		if f.Name != want[i] {
+			t.Errorf("entry %d is %q, want %q", i, f.Name, want[i])
+		}
+		if !f.Modified.Equal(bundleModTime) {
+			t.Errorf("%s has modified time %v", f.Name, f.Modified)
+		}
+	}
+	if mode := r.File[2].Mode().Perm(); mode != 0755 {
+		t.Errorf("executable mode is %v, want 0755", mode)
+	}
+	if mode := r.File[0].Mode().Perm(); mode != 0644 {
+		t.Errorf("file mode is %v, want 0644", mode)
+	}
+}
+
+func TestCompressDirectoryTruncatesExistingArchive(t *testing.T) {
+	src := t.TempDir()
+	writeBundleTree(t, src, time.Now(), 0755)
+
+	dest := filepath.Join(t.TempDir(), "bundle.zip")
+	if err := os.WriteFile(dest, bytes.Repeat([]byte("x"), 1<<20), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := CompressDirectory(src, dest, BundleFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh := filepath.Join(t.TempDir(), "fresh.zip")
+	if _, _, err := CompressDirectory(src, fresh, BundleFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := os.ReadFile(dest)
+	b, _ := os.ReadFile(fresh)
+	if !bytes.Equal(a, b) {
+		t.Fatal("overwritten bundle differs from a fresh one")
+	}
+}
+
+func TestCompressDirectorySkipsArchiveInsideSource(t *testing.T) {
+	src := t.TempDir()
+	writeBundleTree(t, src, time.Now(), 0755)
+
+	dest := filepath.Join(src, "bundle.zip")
+	if _, _, err := CompressDirectory(src, dest, BundleFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.OpenReader(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+	for _, f := range r.File {
+		if f.Name == "bundle.zip" {
+			t.Fatal("archive contains itself")
+		}
+	}
+}
+
+func TestGenerateBundleSignatureIgnoresArchiveEncoding(t *testing.T) {
+	src := t.TempDir()
+	writeBundleTree(t, src, time.Now(), 0755)
+
+	dest := filepath.Join(t.TempDir(), "bundle.zip")
+	bundle, err := GenerateBundleSignature(src, dest, BundleFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// index.js is "module.exports = {};\n".
+	const indexHash = "8222b8169ee86f25cdccd84d340340060ae3f0cff55e2ea9d344d7c332733b71"
+	if got := bundle.FileHashes["index.js"]; got != indexHash {
+		t.Errorf("index.js hash is %s, want %s", got, indexHash)
+	}
+	if len(bundle.FileHashes) != 4 {
+		t.Errorf("got %d file hashes, want 4", len(bundle.FileHashes))
+	}
+	if bundle.Signature != ContentSignature(bundle.FileHashes) {
+		t.Error("signature does not match the file hashes")
+	}
+
+	// Re-encoding the same files with a different compression method leaves the
+	// archive bytes, but not the signature, changed.
+	r, err := zip.OpenReader(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+
+	reencoded := new(bytes.Buffer)
+	w := zip.NewWriter(reencoded)
+	hashes := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := new(bytes.Buffer)
+		_, _ = content.ReadFrom(rc)
+		_ = rc.Close()
+
+		entry, _ := w.CreateHeader(&zip.FileHeader{Name: f.Name, Method: zip.Store})
+		_, _ = entry.Write(content.Bytes())
+		hashes[f.Name] = fmt.Sprintf("%x", sha256.Sum256(content.Bytes()))
+	}
+	_ = w.Close()
+
+	original, _ := os.ReadFile(dest)
+	if bytes.Equal(original, reencoded.Bytes()) {
+		t.Fatal("expected re-encoded archive to differ")
+	}
+	if ContentSignature(hashes) != bundle.Signature {
+		t.Error("signature changed with archive encoding")
+	}
+
+	hashes["index.js"] = hashes["package.json"]
+	if ContentSignature(hashes) == bundle.Signature {
+		t.Error("signature did not change with file contents")
+	}
+}
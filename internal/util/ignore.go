@@ -0,0 +1,206 @@
+package util
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the file at the root of a bundle directory listing, in
+// gitignore syntax, the paths to leave out of the bundle.
+const IgnoreFileName = ".prismaticignore"
+
+// BundleFilter selects the files of a directory that go into a bundle. Patterns
+// are globs matched against slash-separated paths relative to the bundle
+// directory, where "**" matches any number of directories. A pattern matching a
+// directory applies to everything beneath it.
+type BundleFilter struct {
+	// Include, when not empty, limits the bundle to files matching one of these
+	// patterns.
+	Include []string
+	// Exclude leaves out files matching any of these patterns, even if included.
+	Exclude []string
+}
+
+// Validate reports the first malformed pattern in the filter.
+func (f BundleFilter) Validate() error {
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		for _, segment := range strings.Split(pattern, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// bundleMatcher decides whether a file is part of the bundle by combining a
+// BundleFilter with the rules of the directory's ignore file.
+type bundleMatcher struct {
+	filter BundleFilter
+	ignore []ignoreRule
+}
+
+func newBundleMatcher(srcDirectory string, filter BundleFilter) (*bundleMatcher, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	ignore, err := readIgnoreFile(filepath.Join(srcDirectory, IgnoreFileName))
+	if err != nil {
+		return nil, err
+	}
+	return &bundleMatcher{filter: filter, ignore: ignore}, nil
+}
+
+// excludesDir reports whether everything under the directory relPath is left out,
+// so the walk can skip it.
+func (m *bundleMatcher) excludesDir(relPath string) bool {
+	return matchesAnyPattern(m.filter.Exclude, relPath) || m.ignored(relPath, true)
+}
+
+// includesFile reports whether the file relPath belongs in the bundle. Its parent
+// directories are expected to have been checked with excludesDir already.
+func (m *bundleMatcher) includesFile(relPath string) bool {
+	if len(m.filter.Include) > 0 && !matchesPathOrParent(m.filter.Include, relPath) {
+		return false
+	}
+	return !matchesAnyPattern(m.filter.Exclude, relPath) && !m.ignored(relPath, false)
+}
+
+// ignored applies the ignore file rules in order; as with gitignore, the last
+// matching rule wins and a "!" rule re-includes a path.
+func (m *bundleMatcher) ignored(relPath string, isDir bool) bool {
+	ignored := false
+	for _, rule := range m.ignore {
+		if rule.matches(relPath, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func matchesAnyPattern(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPathOrParent reports whether one of patterns matches relPath or one of
+// its parent directories.
+func matchesPathOrParent(patterns []string, relPath string) bool {
+	for p := relPath; p != "."; p = path.Dir(p) {
+		if matchesAnyPattern(patterns, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash-separated path against a glob whose "**" segments
+// match zero or more path segments.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			pattern = pattern[1:]
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// ignoreRule is one line of an ignore file.
+type ignoreRule struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+func (r ignoreRule) matches(relPath string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	return matchGlob(r.pattern, relPath)
+}
+
+// readIgnoreFile parses a gitignore-style file, returning no rules if it does not
+// exist.
+func readIgnoreFile(filename string) ([]ignoreRule, error) {
+	file, err := os.Open(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreLine(scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func parseIgnoreLine(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	// A pattern containing a slash is relative to the bundle directory; one
+	// without matches at any depth.
+	if strings.Contains(line, "/") {
+		rule.pattern = strings.TrimPrefix(line, "/")
+	} else {
+		rule.pattern = "**/" + line
+	}
+	return rule, true
+}
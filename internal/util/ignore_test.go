@@ -0,0 +1,99 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.js", "index.js", true},
+		{"*.js", "lib/index.js", false},
+		{"**/*.js", "lib/index.js", true},
+		{"**/*.js", "index.js", true},
+		{"lib/**", "lib/a/b.js", true},
+		{"lib/**/b.js", "lib/b.js", true},
+		{"lib/**/b.js", "lib/x/y/b.js", true},
+		{"lib/*.js", "lib/x/b.js", false},
+		{"node_modules", "node_modules", true},
+		{"/dist/", "dist", true},
+	}
+	for _, tc := range cases {
+		if got := matchGlob(tc.pattern, tc.name); got != tc.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestListBundleFilesFilters(t *testing.T) {
+	src := t.TempDir()
+	for _, name := range []string{
+		"index.js",
+		"index.js.map",
+		"package.json",
+		"lib/actions.js",
+		"lib/actions.js.map",
+		"lib/keep.js.map",
+		"test/fixture.json",
+		"node_modules/.cache/blob",
+		"node_modules/dep/index.js",
+		".git/HEAD",
+		"build/out.js",
+	} {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignore := "# comment\n*.map\n!lib/keep.js.map\n.git/\n/build\ntest/\n"
+	if err := os.WriteFile(filepath.Join(src, IgnoreFileName), []byte(ignore), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "bundle.zip")
+
+	got, err := listBundleFiles(src, dest, BundleFilter{Exclude: []string{"node_modules/.cache"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		IgnoreFileName,
+		"index.js",
+		"lib/actions.js",
+		"lib/keep.js.map",
+		"node_modules/dep/index.js",
+		"package.json",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got, err = listBundleFiles(src, dest, BundleFilter{
+		Include: []string{"lib", "package.json"},
+		Exclude: []string{"**/keep.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"lib/actions.js", "package.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBundleFilterValidate(t *testing.T) {
+	if err := (BundleFilter{Include: []string{"lib/**"}, Exclude: []string{"*.map"}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (BundleFilter{Exclude: []string{"lib/[.js"}}).Validate(); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
@@ -1,12 +1,16 @@
 package util
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 )
 
-func UploadFile(localPath string, uploadUrl string, contentType string) error {
+// UploadFile PUTs the file at localPath to uploadUrl using client. The body can be
+// re-read, so a retrying client may resend it.
+func UploadFile(ctx context.Context, client *http.Client, localPath string, uploadUrl string, contentType string) error {
 	file, err := os.Open(localPath)
 	if err != nil {
 		return err
@@ -18,20 +22,23 @@ func UploadFile(localPath string, uploadUrl string, contentType string) error {
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPut, uploadUrl, file)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadUrl, file)
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", contentType)
 	req.ContentLength = stat.Size()
+	req.GetBody = func() (io.ReadCloser, error) {
+		return os.Open(localPath)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode > 400 {
+	if resp.StatusCode >= 400 {
 		return fmt.Errorf("upload attempt returned an error: %d", resp.StatusCode)
 	}
 
@@ -0,0 +1,164 @@
+package util
+
+import (
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy bounds how transient failures are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt; 0 disables retries.
+	MaxRetries int
+	// MaxWait caps a single wait between attempts, including one requested by a
+	// Retry-After header.
+	MaxWait time.Duration
+}
+
+const retryBaseWait = 500 * time.Millisecond
+
+// NewRetryTransport wraps base so that idempotent requests failing with a network
+// error, a 429 or a 5xx response are retried with exponential backoff and jitter,
+// honoring Retry-After. GraphQL mutations are never retried automatically since
+// they may have taken effect before the failure.
+func NewRetryTransport(base http.RoundTripper, policy RetryPolicy) http.RoundTripper {
+	return &retryTransport{base: base, policy: policy}
+}
+
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.policy.MaxRetries <= 0 || !isIdempotent(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.policy.MaxRetries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait before the next attempt: the server's Retry-After when
+// given, otherwise an exponentially growing wait with full jitter, capped at
+// MaxWait either way.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	wait := retryBaseWait << attempt
+	if wait <= 0 || wait > t.policy.MaxWait {
+		wait = t.policy.MaxWait
+	}
+	wait = time.Duration(rand.Int63n(int64(wait) + 1))
+
+	if resp != nil {
+		if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			wait = after
+		}
+	}
+	if wait > t.policy.MaxWait {
+		wait = t.policy.MaxWait
+	}
+	return wait
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusNotImplemented:
+		return false
+	case resp.StatusCode >= 500:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIdempotent reports whether req can safely be sent again. Requests with a body
+// must also be replayable. A POST is idempotent only when it is a GraphQL query.
+func isIdempotent(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return isGraphQLQuery(req)
+	default:
+		return false
+	}
+}
+
+// isGraphQLQuery reports whether req carries a GraphQL query operation (as opposed
+// to a mutation or subscription). Anonymous operations ("{ ... }") are queries.
+func isGraphQLQuery(req *http.Request) bool {
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	defer func() { _ = body.Close() }()
+
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return false
+	}
+	operation := strings.TrimSpace(payload.Query)
+	return strings.HasPrefix(operation, "{") || strings.HasPrefix(operation, "query")
+}
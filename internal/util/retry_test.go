@@ -0,0 +1,82 @@
+package util
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	cases := []struct {
+		name         string
+		body         string
+		statuses     []int
+		wantAttempts int32
+		wantStatus   int
+	}{
+		{"query retried on 503", `{"query":"{viewer{id}}"}`, []int{503, 503, 200}, 3, 200},
+		{"named query retried on 429", `{"query":"query($id:ID!){component(id:$id){id}}"}`, []int{429, 200}, 2, 200},
+		{"mutation not retried", `{"query":"mutation($input:X!){x(input:$input){id}}"}`, []int{503, 200}, 1, 503},
+		{"client error not retried", `{"query":"{viewer{id}}"}`, []int{400, 200}, 1, 400},
+		{"gives up after max retries", `{"query":"{viewer{id}}"}`, []int{502, 502, 502, 502, 200}, 3, 502},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&attempts, 1)
+				buf := new(bytes.Buffer)
+				_, _ = buf.ReadFrom(r.Body)
+				if buf.String() != tc.body {
+					t.Errorf("attempt %d sent body %q, want %q", n, buf.String(), tc.body)
+				}
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(tc.statuses[n-1])
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, RetryPolicy{MaxRetries: 2, MaxWait: time.Second})}
+			resp, err := client.Post(server.URL, "application/json", bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tc.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+			if attempts != tc.wantAttempts {
+				t.Errorf("got %d attempts, want %d", attempts, tc.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d, ok := retryAfter("7"); !ok || d != 7*time.Second {
+		t.Errorf("retryAfter(\"7\") = %v, %v", d, ok)
+	}
+	if d, ok := retryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)); !ok || d != 0 {
+		t.Errorf("retryAfter(past date) = %v, %v", d, ok)
+	}
+	if _, ok := retryAfter("soon"); ok {
+		t.Error("retryAfter(\"soon\") should not parse")
+	}
+}
+
+func TestBackoffCappedAtMaxWait(t *testing.T) {
+	transport := &retryTransport{policy: RetryPolicy{MaxRetries: 5, MaxWait: 2 * time.Second}}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"120"}}}
+	if wait := transport.backoff(0, resp); wait != 2*time.Second {
+		t.Errorf("Retry-After wait = %v, want 2s", wait)
+	}
+	for attempt := 0; attempt < 40; attempt++ {
+		if wait := transport.backoff(attempt, nil); wait < 0 || wait > 2*time.Second {
+			t.Fatalf("attempt %d waited %v, outside [0, 2s]", attempt, wait)
+		}
+	}
+}
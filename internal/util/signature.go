@@ -1,38 +1,51 @@
 package util
 
 import (
-	"crypto/sha1"
+	"crypto/sha256"
 	"fmt"
-	"io"
-	"os"
+	"sort"
 )
 
-func GetSha1Signature(path string) (string, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return "", err
-	}
-	defer func() { _ = file.Close() }()
+// Bundle describes a component bundle written by GenerateBundleSignature.
+type Bundle struct {
+	// Path is the location of the bundle archive.
+	Path string
+	// Signature identifies the bundle's contents; see ContentSignature.
+	Signature string
+	// FileHashes maps each file's path in the bundle to the hex SHA-256 of its
+	// contents.
+	FileHashes map[string]string
+}
 
-	hash := sha1.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", err
+// GenerateBundleSignature compresses bundleDirectory into bundlePath and signs the
+// result by its contents.
+func GenerateBundleSignature(bundleDirectory string, bundlePath string, filter BundleFilter) (*Bundle, error) {
+	packagePath, fileHashes, err := CompressDirectory(bundleDirectory, bundlePath, filter)
+	if err != nil {
+		return nil, err
 	}
 
-	sha := fmt.Sprintf("%x", hash.Sum(nil))
-	return sha, nil
+	return &Bundle{
+		Path:       packagePath,
+		Signature:  ContentSignature(fileHashes),
+		FileHashes: fileHashes,
+	}, nil
 }
 
-func GenerateBundleSignature(bundleDirectory string, bundlePath string) (string, string, error) {
-	packagePath, err := CompressDirectory(bundleDirectory, bundlePath)
-	if err != nil {
-		return "", "", err
+// ContentSignature returns the hex SHA-256 of a manifest listing each file's hash
+// and path, one "<sha256>  <path>\n" line per file sorted by path (the format of
+// sha256sum). It depends only on file paths and contents, not on how the archive
+// is encoded.
+func ContentSignature(fileHashes map[string]string) string {
+	paths := make([]string, 0, len(fileHashes))
+	for path := range fileHashes {
+		paths = append(paths, path)
 	}
+	sort.Strings(paths)
 
-	packageSignature, err := GetSha1Signature(packagePath)
-	if err != nil {
-		return "", "", err
+	manifest := sha256.New()
+	for _, path := range paths {
+		_, _ = fmt.Fprintf(manifest, "%s  %s\n", fileHashes[path], path)
 	}
-
-	return packagePath, packageSignature, nil
+	return fmt.Sprintf("%x", manifest.Sum(nil))
 }